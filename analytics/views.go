@@ -0,0 +1,227 @@
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+var (
+	// satu visitor hanya dihitung sekali per artikel dalam jendela ini
+	DedupWindow = 30 * time.Minute
+	// interval dan ukuran batch penulisan event ke article_views
+	FlushInterval = 5 * time.Second
+	BatchSize     = 100
+	// interval rollup harian ke article_view_daily + articles.views
+	RollupInterval = 1 * time.Hour
+	// event mentah lebih tua dari ini dihapus setelah di-rollup
+	RawRetention = 90 * 24 * time.Hour
+)
+
+// salt untuk hash visitor agar IP tidak tersimpan mentah
+var hashSalt = "codetech-views"
+
+// potongan user-agent yang dianggap bot / crawler
+var botPatterns = []string{
+	"bot", "crawl", "spider", "slurp", "mediapartners",
+	"facebookexternalhit", "embedly", "preview", "headless",
+	"lighthouse", "pingdom", "uptime", "monitor",
+	"curl", "wget", "python-requests", "go-http-client", "okhttp", "axios",
+}
+
+type ViewEvent struct {
+	ArticleId   int
+	VisitorHash string
+	ViewedAt    time.Time
+}
+
+type viewPipeline struct {
+	mu     sync.Mutex
+	seen   map[string]time.Time
+	events chan ViewEvent
+}
+
+var pipeline *viewPipeline
+
+// IsBot mengecek apakah user-agent berasal dari bot / crawler
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, p := range botPatterns {
+		if strings.Contains(ua, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// VisitorHash membuat identitas visitor dari IP + user-agent yang sudah di-hash
+func VisitorHash(ip, userAgent string) string {
+	sum := sha256.Sum256([]byte(hashSalt + "|" + ip + "|" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// StartViewPipeline menjalankan worker penulisan batch dan rollup harian
+func StartViewPipeline() {
+	pipeline = &viewPipeline{
+		seen:   make(map[string]time.Time),
+		events: make(chan ViewEvent, BatchSize*10),
+	}
+
+	go pipeline.run()
+	go rollupLoop()
+}
+
+// RecordView mencatat view artikel, return false jika view diabaikan (bot / duplikat)
+func RecordView(articleId int, ip, userAgent string) bool {
+	if pipeline == nil || IsBot(userAgent) {
+		return false
+	}
+
+	hash := VisitorHash(ip, userAgent)
+	key := strconv.Itoa(articleId) + ":" + hash
+	now := time.Now()
+
+	pipeline.mu.Lock()
+	if last, ok := pipeline.seen[key]; ok && now.Sub(last) < DedupWindow {
+		pipeline.mu.Unlock()
+		return false
+	}
+	pipeline.seen[key] = now
+	pipeline.mu.Unlock()
+
+	select {
+	case pipeline.events <- ViewEvent{ArticleId: articleId, VisitorHash: hash, ViewedAt: now}:
+		return true
+	default:
+		// buffer penuh, event dibuang agar request tidak tertahan
+		log.Printf("analytics: view buffer full, dropping event for article %d", articleId)
+		return false
+	}
+}
+
+func (p *viewPipeline) run() {
+	ticker := time.NewTicker(FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ViewEvent, 0, BatchSize)
+	for {
+		select {
+		case ev := <-p.events:
+			batch = append(batch, ev)
+			if len(batch) >= BatchSize {
+				batch = flush(batch)
+			}
+		case <-ticker.C:
+			batch = flush(batch)
+			p.prune()
+		}
+	}
+}
+
+// prune menghapus entry dedup yang sudah lewat jendela
+func (p *viewPipeline) prune() {
+	cutoff := time.Now().Add(-DedupWindow)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, last := range p.seen {
+		if last.Before(cutoff) {
+			delete(p.seen, key)
+		}
+	}
+}
+
+func flush(batch []ViewEvent) []ViewEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	values := make([]string, 0, len(batch))
+	args := make([]interface{}, 0, len(batch)*3)
+	for i, ev := range batch {
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d)", i*3+1, i*3+2, i*3+3))
+		args = append(args, ev.ArticleId, ev.VisitorHash, ev.ViewedAt)
+	}
+
+	query := "INSERT INTO article_views (article_id, visitor_hash, viewed_at) VALUES " + strings.Join(values, ", ")
+	if _, err := config.DB.Exec(query, args...); err != nil {
+		log.Printf("analytics: failed to write %d view events: %s", len(batch), err)
+	}
+
+	return batch[:0]
+}
+
+func rollupLoop() {
+	for {
+		if err := Rollup(); err != nil {
+			log.Printf("analytics: rollup failed: %s", err)
+		}
+		time.Sleep(RollupInterval)
+	}
+}
+
+// Rollup mengagregasi event hari-hari yang sudah lewat ke article_view_daily
+// dan menambahkan hasilnya ke articles.views. Tiap event hanya dihitung sekali
+// (ditandai rolled_up), event yang telat masuk ditambahkan ke hari yang sudah
+// di-rollup, sehingga aman dipanggil berulang kali.
+func Rollup() error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		WITH claimed AS (
+			UPDATE article_views
+			SET rolled_up = TRUE
+			WHERE rolled_up = FALSE AND viewed_at < CURRENT_DATE
+			RETURNING article_id, viewed_at
+		), daily AS (
+			INSERT INTO article_view_daily (article_id, day, views)
+			SELECT article_id, viewed_at::date, COUNT(*)
+			FROM claimed
+			GROUP BY article_id, viewed_at::date
+			ON CONFLICT (article_id, day) DO UPDATE SET views = article_view_daily.views + EXCLUDED.views
+		)
+		UPDATE articles a
+		SET views = a.views + s.total
+		FROM (SELECT article_id, COUNT(*) AS total FROM claimed GROUP BY article_id) s
+		WHERE a.id = s.article_id
+	`)
+	if err != nil {
+		return err
+	}
+
+	// hapus event mentah yang sudah melewati masa simpan
+	_, err = tx.Exec(`DELETE FROM article_views WHERE viewed_at < $1`, time.Now().Add(-RawRetention))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ViewsInRangeSQL menggabungkan rollup harian dengan event mentah yang belum
+// di-rollup, menghasilkan kolom (article_id, day, views). Parameter $1 dan $2
+// adalah tanggal awal dan akhir (inklusif).
+const ViewsInRangeSQL = `
+	SELECT article_id, day, views
+	FROM article_view_daily
+	WHERE day BETWEEN $1 AND $2
+	UNION ALL
+	SELECT v.article_id, v.viewed_at::date AS day, COUNT(*) AS views
+	FROM article_views v
+	WHERE v.viewed_at::date BETWEEN $1 AND $2
+	AND v.rolled_up = FALSE
+	GROUP BY v.article_id, v.viewed_at::date`
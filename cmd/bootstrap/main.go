@@ -0,0 +1,97 @@
+// bootstrap membuat akun superadmin pertama, hanya bisa dijalankan selama
+// belum ada superadmin di database. User lain dibuat lewat undangan.
+//
+//	go run ./cmd/bootstrap -email admin@example.com -name "Super Admin"
+//
+// Jika -password kosong, BOOTSTRAP_PASSWORD dipakai; jika juga kosong,
+// password acak dibuat dan dicetak sekali ke terminal.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+)
+
+func main() {
+	email := flag.String("email", "", "email superadmin (wajib)")
+	name := flag.String("name", "Super Admin", "nama superadmin")
+	password := flag.String("password", os.Getenv("BOOTSTRAP_PASSWORD"), "password, minimal 8 karakter")
+	flag.Parse()
+
+	config.InitValidator()
+
+	*email = strings.ToLower(strings.TrimSpace(*email))
+	if err := config.Validate.Var(*email, "required,email"); err != nil {
+		log.Fatal("-email wajib diisi dengan email yang valid")
+	}
+
+	generated := false
+	if *password == "" {
+		*password = utils.RandomToken(12)
+		generated = true
+	}
+	if len(*password) < 8 || len(*password) > 72 {
+		log.Fatal("password harus 8-72 karakter")
+	}
+
+	config.ConnectDB()
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	// kunci tabel agar dua bootstrap bersamaan tidak sama-sama lolos
+	if _, err := tx.Exec(`LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		log.Fatal(err)
+	}
+
+	var superadmins int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM users WHERE role = $1`, model.RoleSuperadmin).Scan(&superadmins); err != nil {
+		log.Fatal(err)
+	}
+	if superadmins > 0 {
+		log.Fatal("superadmin sudah ada, bootstrap dibatalkan; gunakan undangan untuk user baru")
+	}
+
+	var existing int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM users WHERE LOWER(email) = $1`, *email).Scan(&existing); err != nil {
+		log.Fatal(err)
+	}
+	if existing > 0 {
+		log.Fatal("email sudah dipakai user lain")
+	}
+
+	hashedPassword, err := utils.HashPassword(*password)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO users (name, email, password, profile, role, created_at, updated_at)
+		VALUES ($1, $2, $3, '', $4, $5, $5)
+		RETURNING id
+	`, strings.TrimSpace(*name), *email, hashedPassword, model.RoleSuperadmin, time.Now()).Scan(&id)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Superadmin %s dibuat (id %d) ✅\n", *email, id)
+	if generated {
+		fmt.Printf("Password: %s\nSegera ganti lewat PUT /api/admin/users/me/password\n", *password)
+	}
+}
@@ -0,0 +1,44 @@
+// jwtkey membuat kunci penanda tangan JWT baru di JWT_KEYS_DIR (langkah pertama
+// rotasi kunci, lihat package jwtkeys) lalu menampilkan kid dan JWKS hasilnya.
+//
+//	go run ./cmd/jwtkey -alg EdDSA
+//	go run ./cmd/jwtkey -alg RS256 -kid 2026-q4
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/jwtkeys"
+)
+
+func main() {
+	alg := flag.String("alg", jwtkeys.AlgEdDSA, "algoritma: EdDSA atau RS256")
+	kid := flag.String("kid", "", "key id, default berbasis waktu pembuatan")
+	dir := flag.String("dir", config.JWTKeysDir, "folder kunci")
+	flag.Parse()
+
+	path, err := jwtkeys.GenerateFile(*dir, *alg, *kid)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	set, err := jwtkeys.Load(*dir, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	newKid := strings.TrimSuffix(filepath.Base(path), ".pem")
+	fmt.Printf("Kunci %s dibuat di %s ✅\n", newKid, path)
+	fmt.Println("Set JWT_SIGNING_KID ke kid ini setelah service lain memperbarui JWKS.")
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(set.JWKS())
+}
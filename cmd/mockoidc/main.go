@@ -0,0 +1,228 @@
+// mockoidc adalah identity provider OIDC tiruan untuk mencoba login SSO secara
+// lokal tanpa provider sungguhan. Halaman authorize langsung menyetujui login
+// sebagai -email (atau ?login_hint=), PKCE S256 diperiksa di token endpoint.
+//
+//	go run ./cmd/mockoidc -email admin@codetech.test
+//	OIDC_ISSUER=http://localhost:9090 OIDC_CLIENT_ID=codetech go run .
+//	buka http://localhost:8080/api/oidc/login
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const kid = "mock"
+
+type authCode struct {
+	clientID      string
+	redirectURI   string
+	challenge     string
+	nonce         string
+	email         string
+	expiresAt     time.Time
+	emailVerified bool
+}
+
+var (
+	issuer        = flag.String("issuer", "http://localhost:9090", "issuer URL (harus sama dengan OIDC_ISSUER)")
+	addr          = flag.String("addr", ":9090", "alamat listen")
+	clientID      = flag.String("client-id", "codetech", "client_id yang diterima")
+	email         = flag.String("email", "admin@codetech.test", "email user yang login")
+	name          = flag.String("name", "Mock Admin", "nama user yang login")
+	emailVerified = flag.Bool("email-verified", true, "nilai claim email_verified")
+	mfa           = flag.Bool("mfa", false, "laporkan login multi-faktor (amr berisi \"mfa\")")
+
+	privateKey *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes = map[string]authCode{}
+)
+
+func randomString() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                *issuer,
+		"authorization_endpoint":                *issuer + "/authorize",
+		"token_endpoint":                        *issuer + "/token",
+		"jwks_uri":                              *issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func jwks(w http.ResponseWriter, r *http.Request) {
+	pub := privateKey.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": kid,
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize langsung menyetujui login lalu redirect kembali dengan code
+func authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	if q.Get("client_id") != *clientID || redirectURI == "" {
+		http.Error(w, "invalid client_id or redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "authorization code with PKCE S256 required", http.StatusBadRequest)
+		return
+	}
+
+	loginEmail := *email
+	if hint := q.Get("login_hint"); hint != "" {
+		loginEmail = hint
+	}
+
+	code := randomString()
+	mu.Lock()
+	codes[code] = authCode{
+		clientID:      *clientID,
+		redirectURI:   redirectURI,
+		challenge:     q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		email:         loginEmail,
+		expiresAt:     time.Now().Add(time.Minute),
+		emailVerified: *emailVerified,
+	}
+	mu.Unlock()
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+
+	log.Printf("authorize: %s -> %s", loginEmail, redirectURI)
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "authorization_code required")
+		return
+	}
+
+	client := r.PostForm.Get("client_id")
+	if user, _, ok := r.BasicAuth(); ok {
+		client, _ = url.QueryUnescape(user)
+	}
+
+	// code sekali pakai
+	mu.Lock()
+	auth, ok := codes[r.PostForm.Get("code")]
+	delete(codes, r.PostForm.Get("code"))
+	mu.Unlock()
+
+	if !ok || time.Now().After(auth.expiresAt) {
+		tokenError(w, "invalid_grant", "unknown or expired code")
+		return
+	}
+	if client != auth.clientID || r.PostForm.Get("redirect_uri") != auth.redirectURI {
+		tokenError(w, "invalid_grant", "client_id or redirect_uri mismatch")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge {
+		tokenError(w, "invalid_grant", "PKCE verification failed")
+		return
+	}
+
+	amr := []string{"pwd"}
+	if *mfa {
+		amr = append(amr, "mfa")
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            *issuer,
+		"sub":            "mock|" + strings.ToLower(auth.email),
+		"aud":            auth.clientID,
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          auth.nonce,
+		"email":          auth.email,
+		"email_verified": auth.emailVerified,
+		"name":           *name,
+		"amr":            amr,
+	})
+	idToken.Header["kid"] = kid
+	signed, err := idToken.SignedString(privateKey)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+func main() {
+	flag.Parse()
+	*issuer = strings.TrimRight(*issuer, "/")
+
+	var err error
+	privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	http.HandleFunc("/.well-known/openid-configuration", discovery)
+	http.HandleFunc("/jwks", jwks)
+	http.HandleFunc("/authorize", authorize)
+	http.HandleFunc("/token", token)
+
+	log.Printf("mock OIDC provider %s (client_id=%s, email=%s) listening on %s", *issuer, *clientID, *email, *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+// kunci HMAC untuk token yang dikirim lewat email (reset password, undangan) dan
+// challenge 2FA, wajib diisi (minimal 32 karakter), contoh: openssl rand -base64 48
+var AppKey = getEnv("APP_KEY", "")
+
+// CheckAppKey dipanggil saat start, server tidak jalan dengan APP_KEY kosong / lemah
+func CheckAppKey() error {
+	if AppKey == "" || AppKey == "secret-codetech" {
+		return errors.New("APP_KEY is not set")
+	}
+	if len(AppKey) < 32 {
+		return errors.New("APP_KEY must be at least 32 characters")
+	}
+	return nil
+}
+
+// masa berlaku link reset password
+var PasswordResetTTL = 1 * time.Hour
+
+// halaman frontend untuk form reset password, token ditambahkan sebagai ?token=
+var PasswordResetURL = getEnv("PASSWORD_RESET_URL", SiteURL+"/reset-password")
+
+// masa berlaku default link undangan user baru
+var InvitationTTL = 72 * time.Hour
+
+// halaman frontend untuk menerima undangan, token ditambahkan sebagai ?token=
+var InvitationURL = getEnv("INVITATION_URL", SiteURL+"/accept-invitation")
+
+// proteksi brute-force login: setelah FreeAttempts kali gagal, percobaan berikutnya
+// ditahan LoginBackoffBase * 2^(gagal - FreeAttempts), maksimal LoginMaxLockout
+var (
+	LoginAccountFreeAttempts = 3
+	LoginIPFreeAttempts      = 10
+	LoginBackoffBase         = 30 * time.Second
+	LoginMaxLockout          = 1 * time.Hour
+	// counter gagal mulai dari nol lagi jika tidak ada kegagalan selama ini
+	LoginFailureWindow = 24 * time.Hour
+)
+
+// role yang wajib 2FA: login tetap bisa, tapi sampai 2FA aktif hanya route
+// pendaftaran 2FA (dan profil sendiri) yang boleh diakses
+var TwoFactorRequiredRoles = []string{"superadmin", "admin"}
+
+func TwoFactorRequired(role string) bool {
+	for _, r := range TwoFactorRequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// masa berlaku token langkah kedua login (setelah password benar, sebelum kode 2FA)
+var TwoFactorChallengeTTL = 5 * time.Minute
+
+// challenge dibatalkan setelah kode salah sebanyak ini, user harus login ulang
+var TwoFactorChallengeMaxAttempts = 5
+
+// jumlah recovery code yang dibuat sekali enroll / regenerate
+var RecoveryCodeCount = 10
+
+// JWT login ditandatangani kunci asimetris (RS256 / EdDSA) dari folder JWT_KEYS_DIR,
+// satu file PEM per kunci dengan nama <kid>.pem. Semua kunci di folder dipakai
+// untuk verifikasi dan dipublikasikan di /.well-known/jwks.json; JWT_SIGNING_KID
+// memilih kunci penanda tangan (default: kid terakhir menurut urutan nama).
+var (
+	JWTKeysDir    = getEnv("JWT_KEYS_DIR", "keys")
+	JWTSigningKid = getEnv("JWT_SIGNING_KID", "")
+	JWTIssuer     = getEnv("JWT_ISSUER", "codetech")
+	JWTTTL        = 1 * time.Hour
+	// hanya untuk development: buat kunci Ed25519 jika folder kosong. Di production
+	// semua instance harus memakai kunci yang sama, jadi server gagal start tanpa kunci
+	JWTAutoGenerateKeys = getEnv("JWT_KEYS_AUTOGENERATE", "false") == "true"
+)
+
+// masa berlaku default API key jika expires_in_days tidak dikirim
+var APIKeyDefaultTTL = 90 * 24 * time.Hour
@@ -0,0 +1,18 @@
+package config
+
+// pengaturan pengiriman email
+//
+// MAIL_DRIVER:
+//   - smtp: kirim lewat SMTP (untuk development bisa diarahkan ke SMTP lokal
+//     seperti MailHog / smtp4dev di localhost:1025)
+//   - file: simpan tiap email sebagai file .eml di MAIL_DIR
+//   - log: cetak email ke log aplikasi
+var (
+	MailDriver   = getEnv("MAIL_DRIVER", "smtp")
+	MailDir      = getEnv("MAIL_DIR", "mails")
+	SMTPHost     = getEnv("SMTP_HOST", "localhost")
+	SMTPPort     = getEnv("SMTP_PORT", "1025")
+	SMTPUsername = getEnv("SMTP_USERNAME", "")
+	SMTPPassword = getEnv("SMTP_PASSWORD", "")
+	MailFrom     = getEnv("MAIL_FROM", "no-reply@codetech.crx.my.id")
+)
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// single sign-on OIDC (authorization code + PKCE), nonaktif jika OIDC_ISSUER kosong.
+// Untuk development bisa memakai provider tiruan: go run ./cmd/mockoidc
+var (
+	OIDCIssuer       = strings.TrimRight(getEnv("OIDC_ISSUER", ""), "/")
+	OIDCClientID     = getEnv("OIDC_CLIENT_ID", "")
+	OIDCClientSecret = getEnv("OIDC_CLIENT_SECRET", "") // kosong = public client (PKCE saja)
+	// callback API ini yang didaftarkan di identity provider
+	OIDCRedirectURL = getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/api/oidc/callback")
+	// batas waktu dari redirect ke identity provider sampai callback
+	OIDCStateTTL = 10 * time.Minute
+	// halaman frontend penerima hasil login: #token=... atau #error=...
+	OIDCFrontendURL = getEnv("OIDC_FRONTEND_URL", SiteURL+"/auth/sso")
+	// user baru dibuat otomatis dari login SSO hanya jika OIDC_DEFAULT_ROLE dan
+	// OIDC_ALLOWED_DOMAINS (pisahkan koma) keduanya diisi; selain itu hanya user
+	// yang sudah ada yang bisa login SSO
+	OIDCDefaultRole    = getEnv("OIDC_DEFAULT_ROLE", "")
+	OIDCAllowedDomains = splitList(getEnv("OIDC_ALLOWED_DOMAINS", ""))
+	// login SSO yang MFA-nya dilaporkan identity provider (amr "mfa") dianggap memenuhi
+	// kewajiban 2FA untuk user yang belum enroll TOTP; user yang sudah enroll tetap
+	// wajib memasukkan kode TOTP
+	OIDCSatisfiesTwoFactor = getEnv("OIDC_SATISFIES_2FA", "false") == "true"
+)
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// URL publik website (frontend), dipakai untuk link absolut di feed / sitemap
+var SiteURL = getEnv("SITE_URL", "https://codetech.crx.my.id")
+
+// URL publik API ini, dipakai untuk link absolut file di /uploads, feed & sitemap
+var APIURL = strings.TrimRight(getEnv("API_URL", ""), "/")
+
+// CheckAPIURL dipanggil saat start; link absolut tidak boleh diambil dari header
+// Host / X-Forwarded-Proto karena respons feed & sitemap di-cache publik
+func CheckAPIURL() error {
+	u, err := url.Parse(APIURL)
+	if APIURL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("API_URL must be set to the public http(s) URL of this API")
+	}
+	return nil
+}
+
+var SiteName = getEnv("SITE_NAME", "Codetech")
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func splitList(v string) []string {
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// IP / CIDR reverse proxy yang header X-Forwarded-For-nya dipercaya (pisahkan koma);
+// kosong = tidak ada, IP client diambil dari koneksi langsung
+var TrustedProxies = splitList(getEnv("TRUSTED_PROXIES", ""))
+
+// locale konten: kolom asli tabel berisi DefaultLocale, locale lain disimpan di tabel translations
+var DefaultLocale = "id"
+
+var SupportedLocales = []string{"id", "en"}
+
+func IsSupportedLocale(locale string) bool {
+	for _, l := range SupportedLocales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// mata uang default produk (kode ISO 4217)
+var DefaultCurrency = getEnv("DEFAULT_CURRENCY", "IDR")
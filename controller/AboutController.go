@@ -47,6 +47,14 @@ func GetAllAbout(c *gin.Context) {
 		return
 	}
 
+	translations, err := loadTranslations("abouts", requestLocale(c), []int{about.Id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	translateField(translations[about.Id], "title", &about.Title)
+	translateField(translations[about.Id], "description", &about.Description)
+
 	c.JSON(http.StatusOK, gin.H{
 		"data": about,
 	})
@@ -252,6 +260,8 @@ func DeleteAbout(c *gin.Context) {
 		return
 	}
 
+	deleteTranslations("abouts", id)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
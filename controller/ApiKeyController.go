@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+// <resource>:<read|write>, resource = segmen route admin (articles, faqs, ...) atau *
+var apiKeyScopePattern = regexp.MustCompile(`^(\*|[a-z0-9-]+):(read|write)$`)
+
+func isUserManager(role string) bool {
+	return role == model.RoleSuperadmin || role == model.RoleAdmin
+}
+
+// daftar API key milik sendiri, admin bisa melihat semua dengan ?all=1
+func GetApiKeys(c *gin.Context) {
+	query := `
+		SELECT k.id, k.user_id, k.name, k.prefix, k.scopes, k.expires_at, k.last_used_at, k.revoked_at,
+			k.token_version = u.token_version, k.created_at
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id`
+	var args []interface{}
+	if !(c.Query("all") == "1" && isUserManager(c.GetString("role"))) {
+		query += ` WHERE k.user_id = $1`
+		args = append(args, c.GetInt("user_id"))
+	}
+	query += ` ORDER BY k.created_at DESC`
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	keys := []model.ApiKey{}
+	for rows.Next() {
+		var key model.ApiKey
+		var expiresAt, lastUsedAt, revokedAt sql.NullTime
+		var currentVersion bool
+		if err := rows.Scan(&key.Id, &key.UserId, &key.Name, &key.Prefix, pq.Array(&key.Scopes), &expiresAt, &lastUsedAt, &revokedAt, &currentVersion, &key.CreatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if expiresAt.Valid {
+			key.ExpiresAt = &expiresAt.Time
+		}
+		if lastUsedAt.Valid {
+			key.LastUsedAt = &lastUsedAt.Time
+		}
+		if revokedAt.Valid {
+			key.RevokedAt = &revokedAt.Time
+		}
+		// key dari sebelum ganti / reset password tidak berlaku lagi
+		key.Active = !revokedAt.Valid && currentVersion && (!expiresAt.Valid || expiresAt.Time.After(now))
+		keys = append(keys, key)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+// buat API key baru, key lengkap hanya ditampilkan sekali di response ini
+func CreateApiKey(c *gin.Context) {
+	var req model.ApiKeyRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// scopes=a,b disamakan dengan scopes=a&scopes=b
+	var scopes []string
+	seen := map[string]bool{}
+	for _, value := range req.Scopes {
+		for _, scope := range strings.Split(value, ",") {
+			scope = strings.ToLower(strings.TrimSpace(scope))
+			if scope != "" && !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	req.Scopes = scopes
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		if !apiKeyScopePattern.MatchString(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scope " + scope})
+			return
+		}
+	}
+
+	ttl := config.APIKeyDefaultTTL
+	if req.ExpiresInDays > 0 {
+		ttl = time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	prefix, key := utils.NewAPIKey()
+	var id int
+	err := config.DB.QueryRow(`
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at, token_version, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, (SELECT token_version FROM users WHERE id = $1), $7)
+		RETURNING id
+	`, c.GetInt("user_id"), strings.TrimSpace(req.Name), prefix, utils.HashToken(key), pq.Array(req.Scopes), expiresAt, time.Now()).Scan(&id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Data created successfully",
+		"id":         id,
+		"key":        key,
+		"prefix":     prefix,
+		"scopes":     req.Scopes,
+		"expires_at": expiresAt,
+	})
+}
+
+// cabut API key milik sendiri (admin bisa mencabut key siapa pun)
+func RevokeApiKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	query := `UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	args := []interface{}{time.Now(), id}
+	if !isUserManager(c.GetString("role")) {
+		query += ` AND user_id = $3`
+		args = append(args, c.GetInt("user_id"))
+	}
+
+	result, err := config.DB.Exec(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
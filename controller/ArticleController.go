@@ -9,24 +9,52 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/analytics"
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
 )
 
+// ambil format output deskripsi dari ?format=html|markdown|plain
+func articleOutputFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", utils.ContentFormatHTML)
+	switch format {
+	case utils.ContentFormatHTML, utils.ContentFormatMarkdown, utils.ContentFormatPlain:
+		return format, true
+	}
+	return "", false
+}
+
+// render deskripsi artikel sesuai format + hitung excerpt dan waktu baca
+func renderArticle(art *model.ResponseArticle, format string) {
+	plain := utils.RenderContent(art.Description, art.ContentFormat, utils.ContentFormatPlain)
+	art.Excerpt = utils.Excerpt(plain, 160)
+	art.ReadingTime = utils.ReadingTime(plain)
+	art.Description = utils.RenderContent(art.Description, art.ContentFormat, format)
+}
+
 // get all article
 func GetAllArticle(c *gin.Context) {
 	var articles []model.ResponseArticle
 
+	format, ok := articleOutputFormat(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected html, markdown or plain"})
+		return
+	}
+
 	rows, err := config.DB.Query(`
 		SELECT 
-			a.id, a.title, a.slug, a.description, a.thumbnail, a.views, 
+			a.id, a.title, a.slug, a.description, a.content_format, a.thumbnail, a.views, 
 			a.created_at, a.updated_at, 
 			u.name AS user_name, 
-			c.category AS category_name
+			c.category AS category_name,
+			(SELECT COUNT(*) FROM article_comments cm WHERE cm.article_id = a.id AND cm.status = 'approved') AS comment_count,
+			a.meta_title, a.meta_description, a.canonical_url, a.og_image, a.noindex
 		FROM articles a
 		JOIN users u ON a.user_id = u.id
 		JOIN category_articles c ON a.category_id = c.id
@@ -43,17 +71,25 @@ func GetAllArticle(c *gin.Context) {
 
 	for rows.Next() {
 		var art model.ResponseArticle
+		var seo model.SeoFields
 		if err := rows.Scan(
 			&art.Id,
 			&art.Title,
 			&art.Slug,
 			&art.Description,
+			&art.ContentFormat,
 			&art.Thumbnail,
 			&art.Views,
 			&art.CreatedAt,
 			&art.UpdatedAt,
 			&art.User,
 			&art.Category,
+			&art.CommentCount,
+			&seo.MetaTitle,
+			&seo.MetaDescription,
+			&seo.CanonicalUrl,
+			&seo.OgImage,
+			&seo.Noindex,
 		); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":  "Failed to parse data",
@@ -61,9 +97,22 @@ func GetAllArticle(c *gin.Context) {
 			})
 			return
 		}
+		art.Seo = &seo
 		articles = append(articles, art)
 	}
 
+	refs := make([]*model.ResponseArticle, len(articles))
+	for i := range articles {
+		refs[i] = &articles[i]
+	}
+	if err := translateArticles(requestLocale(c), refs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	for _, art := range refs {
+		renderArticle(art, format)
+	}
+
 	if len(articles) == 0 {
 		c.JSON(http.StatusOK, gin.H{"data": []interface{}{}})
 		return
@@ -97,6 +146,13 @@ func CreateArticle(c *gin.Context) {
 		return
 	}
 
+	// sanitasi deskripsi sesuai format sumber (default html)
+	contentFormat := req.ContentFormat
+	if contentFormat == "" {
+		contentFormat = utils.ContentFormatHTML
+	}
+	description = utils.SanitizeContent(description, contentFormat)
+
 	// Upload file thumbnail
 	file, err := c.FormFile("thumbnail")
 	if err != nil {
@@ -119,11 +175,20 @@ func CreateArticle(c *gin.Context) {
 
 	thumbnail := "/uploads/" + filename
 
+	// Metadata SEO (og_image opsional)
+	seo, err := seoFromRequest(c, req.SeoRequest, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload og image"})
+		return
+	}
+
 	// Simpan ke database (PostgreSQL style)
 	_, err = config.DB.Exec(`
-		INSERT INTO articles (title, slug, user_id, category_id, description, thumbnail, views, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, 0, $7, $8)
-	`, title, slug.Make(title), userID, categoryID, description, thumbnail, time.Now(), time.Now())
+		INSERT INTO articles (title, slug, user_id, category_id, description, content_format, thumbnail, views, created_at, updated_at,
+			meta_title, meta_description, canonical_url, og_image, noindex)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 0, $8, $9, $10, $11, $12, $13, $14)
+	`, title, slug.Make(title), userID, categoryID, description, contentFormat, thumbnail, time.Now(), time.Now(),
+		seo.MetaTitle, seo.MetaDescription, seo.CanonicalUrl, seo.OgImage, seo.Noindex)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -133,6 +198,8 @@ func CreateArticle(c *gin.Context) {
 		return
 	}
 
+	invalidateArticleCache()
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -168,9 +235,17 @@ func UpdateArticle(c *gin.Context) {
 		return
 	}
 
+	// sanitasi deskripsi sesuai format sumber (default html)
+	contentFormat := req.ContentFormat
+	if contentFormat == "" {
+		contentFormat = utils.ContentFormatHTML
+	}
+	description = utils.SanitizeContent(description, contentFormat)
+
 	// Ambil data artikel lama
 	var article model.Article
-	err = config.DB.QueryRow(`SELECT id, thumbnail FROM articles WHERE id = $1`, id).Scan(&article.Id, &article.Thumbnail)
+	var oldOgImage string
+	err = config.DB.QueryRow(`SELECT id, thumbnail, og_image FROM articles WHERE id = $1`, id).Scan(&article.Id, &article.Thumbnail, &oldOgImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
@@ -208,13 +283,22 @@ func UpdateArticle(c *gin.Context) {
 		thumbnail = "/uploads/" + filename
 	}
 
+	// Metadata SEO
+	seo, err := seoFromRequest(c, req.SeoRequest, oldOgImage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload og image"})
+		return
+	}
+
 	// Update data ke database
 	_, err = config.DB.Exec(`
 		UPDATE articles
 		SET title = $1, slug = $2, user_id = $3, category_id = $4,
-			description = $5, thumbnail = $6, updated_at = $7
-		WHERE id = $8
-	`, title, slug.Make(title), userID, categoryID, description, thumbnail, time.Now(), id)
+			description = $5, content_format = $6, thumbnail = $7, updated_at = $8,
+			meta_title = $9, meta_description = $10, canonical_url = $11, og_image = $12, noindex = $13
+		WHERE id = $14
+	`, title, slug.Make(title), userID, categoryID, description, contentFormat, thumbnail, time.Now(),
+		seo.MetaTitle, seo.MetaDescription, seo.CanonicalUrl, seo.OgImage, seo.Noindex, id)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -224,6 +308,8 @@ func UpdateArticle(c *gin.Context) {
 		return
 	}
 
+	invalidateArticleCache()
+
 	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
 }
 
@@ -238,7 +324,8 @@ func DeleteArticle(c *gin.Context) {
 
 	// Cek apakah data ada
 	var article model.Article
-	err = config.DB.QueryRow(`SELECT id, thumbnail FROM articles WHERE id = $1`, id).Scan(&article.Id, &article.Thumbnail)
+	var ogImage string
+	err = config.DB.QueryRow(`SELECT id, thumbnail, og_image FROM articles WHERE id = $1`, id).Scan(&article.Id, &article.Thumbnail, &ogImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
@@ -265,37 +352,33 @@ func DeleteArticle(c *gin.Context) {
 		return
 	}
 
+	removeUploadedFile(ogImage)
+	deleteTranslations("articles", id)
+	invalidateArticleCache()
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
 }
 
-// hitung views artikel
-func IncrementArticleViews(c *gin.Context) {
+// catat view artikel lewat pipeline analytics (dedup per visitor & filter bot)
+func RecordArticleView(c *gin.Context) {
 	slugParam := c.Param("slug")
 
-	// Update kolom views (+1)
-	result, err := config.DB.Exec(`
-		UPDATE articles
-		SET views = views + 1
-		WHERE slug = $1
-	`, slugParam)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "Failed to update views",
-			"detail": err.Error(),
-		})
-		return
-	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	var articleID int
+	err := config.DB.QueryRow(`SELECT id FROM articles WHERE slug = $1`, slugParam).Scan(&articleID)
+	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
 		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Views updated +1",
+	counted := analytics.RecordView(articleID, c.ClientIP(), c.Request.UserAgent())
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "View received",
+		"counted": counted,
 	})
 }
@@ -1,57 +1,133 @@
 package controller
 
 import (
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/analytics"
 	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/jwtkeys"
 	"github.com/gibranfajar/backend-codetech/model"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtSecret = []byte("secret-codetech") // sebaiknya ambil dari ENV
+// generateToken membuat JWT login (expired sesuai config.JWTTTL), "ver" harus sama
+// dengan users.token_version agar token diterima middleware
+func generateToken(userID, tokenVersion int) (string, error) {
+	return jwtkeys.Sign(loginClaims(userID, tokenVersion))
+}
+
+func loginClaims(userID, tokenVersion int) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":     config.JWTIssuer,
+		"sub":     strconv.Itoa(userID),
+		"user_id": userID,
+		"ver":     tokenVersion,
+		"exp":     now.Add(config.JWTTTL).Unix(),
+		"iat":     now.Unix(),
+	}
+}
+
+// kunci publik JWT untuk service lain yang perlu memverifikasi token codetech
+func GetJWKS(c *gin.Context) {
+	set, err := jwtkeys.Default()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load keys", "detail": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set.JWKS())
+}
+
+// hash bcrypt (cost sama dengan utils.HashPassword) untuk login dengan email tidak dikenal
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("codetech-login-dummy"), bcrypt.DefaultCost)
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
 
 func Login(c *gin.Context) {
-	email := c.PostForm("email")
+	email := normalizeEmail(c.PostForm("email"))
 	password := c.PostForm("password")
+	ipHash := analytics.VisitorHash(c.ClientIP(), "")
+	now := time.Now()
 
-	var user model.User
-	err := config.DB.QueryRow(
-		"SELECT id, email, password FROM users WHERE email = $1", email,
-	).Scan(&user.Id, &user.Email, &user.Password)
-
+	// akun / IP yang sedang ditahan tidak dicek password-nya sama sekali
+	wait, err := loginLockedFor(email, ipHash, now)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
 		return
 	}
+	if wait > 0 {
+		recordLoginAttempt(c, email, 0, ipHash, false, "locked")
+		tooManyLoginAttempts(c, wait)
+		return
+	}
+
+	var user model.User
+	var tokenVersion int
+	var twoFactorEnabled bool
+	err = config.DB.QueryRow(
+		"SELECT id, email, password, role, token_version, totp_enabled_at IS NOT NULL FROM users WHERE LOWER(email) = $1", email,
+	).Scan(&user.Id, &user.Email, &user.Password, &user.Role, &tokenVersion, &twoFactorEnabled)
 
-	// Compare password
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	// Compare password; email yang tidak terdaftar tetap dibandingkan dengan hash
+	// dummy agar waktu respons tidak membocorkan email mana yang ada
+	if err == nil {
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	} else {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+	}
 	if err != nil {
+		if err := recordLoginFailure(email, ipHash, now); err != nil {
+			log.Printf("login throttle: %v", err)
+		}
+		recordLoginAttempt(c, email, user.Id, ipHash, false, "invalid_credentials")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
 
-	// Generate JWT token (expired in 1 hour)
-	expirationTime := time.Now().Add(1 * time.Hour)
+	// 2FA aktif: JWT baru diberikan setelah kode diverifikasi di /api/login/2fa,
+	// counter gagal belum di-reset sampai langkah kedua berhasil
+	if twoFactorEnabled {
+		challenge, err := twoFactorChallenge(user.Id, tokenVersion)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":             "Two-factor authentication required",
+			"two_factor_required": true,
+			"challenge_token":     challenge,
+			"expires_in":          int(config.TwoFactorChallengeTTL.Seconds()),
+		})
+		return
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.Id,
-		"exp":     expirationTime.Unix(),
-		"iat":     time.Now().Unix(),
-	})
+	clearLoginFailures(email, now)
+	recordLoginAttempt(c, email, user.Id, ipHash, true, "")
 
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := generateToken(user.Id, tokenVersion)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message": "Login successfully",
 		"token":   tokenString,
-	})
+	}
+	// role wajib 2FA tapi belum enroll: token hanya bisa dipakai untuk setup 2FA
+	if config.TwoFactorRequired(user.Role) {
+		response["two_factor_setup_required"] = true
+	}
+	c.JSON(http.StatusOK, response)
 
 }
@@ -11,29 +11,32 @@ import (
 	"github.com/gibranfajar/backend-codetech/model"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/gosimple/slug"
+	"github.com/lib/pq"
 )
 
 // get all category
 func GetAllCategoryArticle(c *gin.Context) {
 	var categoryArticles []model.CategoryArticle
 
-	rows, err := config.DB.Query("SELECT id, category, created_at, updated_at FROM category_articles")
+	rows, err := config.DB.Query("SELECT id, parent_id, category, slug, description, created_at, updated_at FROM category_articles ORDER BY id ASC")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
 		return
 	}
-
-	if rows == nil {
-		c.JSON(http.StatusOK, gin.H{"data": []interface{}{}})
-		return
-	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var categoryArticle model.CategoryArticle
-		if err := rows.Scan(&categoryArticle.Id, &categoryArticle.Category, &categoryArticle.CreatedAt, &categoryArticle.UpdatedAt); err != nil {
+		var parentID sql.NullInt64
+		if err := rows.Scan(&categoryArticle.Id, &parentID, &categoryArticle.Category, &categoryArticle.Slug, &categoryArticle.Description, &categoryArticle.CreatedAt, &categoryArticle.UpdatedAt); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
 			return
 		}
+		if parentID.Valid {
+			id := int(parentID.Int64)
+			categoryArticle.ParentId = &id
+		}
 		categoryArticles = append(categoryArticles, categoryArticle)
 	}
 
@@ -42,10 +45,85 @@ func GetAllCategoryArticle(c *gin.Context) {
 	})
 }
 
+// id kategori beserta seluruh sub kategorinya
+func categoryWithDescendants(id int) ([]int, error) {
+	rows, err := config.DB.Query(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM category_articles WHERE id = $1
+			UNION
+			SELECT c.id FROM category_articles c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT id FROM tree
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var childID int
+		if err := rows.Scan(&childID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, childID)
+	}
+	return ids, rows.Err()
+}
+
+// validasi slug unik dan parent (tidak boleh diri sendiri / turunannya)
+func categoryArticleInput(c *gin.Context, req model.CategoryArticleRequest, id int) (string, interface{}, bool) {
+	categorySlug := slug.Make(req.Category)
+	if req.Slug != "" {
+		categorySlug = slug.Make(req.Slug)
+	}
+	if categorySlug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid slug"})
+		return "", nil, false
+	}
+
+	var exists int
+	err := config.DB.QueryRow(`SELECT 1 FROM category_articles WHERE slug = $1 AND id <> $2`, categorySlug, id).Scan(&exists)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug already used by another category"})
+		return "", nil, false
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return "", nil, false
+	}
+
+	if req.ParentId == 0 {
+		return categorySlug, nil, true
+	}
+
+	err = config.DB.QueryRow(`SELECT 1 FROM category_articles WHERE id = $1`, req.ParentId).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parent category not found"})
+		return "", nil, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return "", nil, false
+	}
+
+	if id != 0 {
+		descendants, err := categoryWithDescendants(id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return "", nil, false
+		}
+		for _, descendant := range descendants {
+			if descendant == req.ParentId {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Parent category cannot be the category itself or one of its descendants"})
+				return "", nil, false
+			}
+		}
+	}
+
+	return categorySlug, req.ParentId, true
+}
+
 // create category article
 func CreateCategoryArticle(c *gin.Context) {
-	category := c.PostForm("category")
-
 	var req model.CategoryArticleRequest
 	if err := c.ShouldBind(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -62,11 +140,16 @@ func CreateCategoryArticle(c *gin.Context) {
 		return
 	}
 
+	categorySlug, parentID, ok := categoryArticleInput(c, req, 0)
+	if !ok {
+		return
+	}
+
 	// Simpan ke database (PostgreSQL style)
 	_, err := config.DB.Exec(`
-		INSERT INTO category_articles (category, created_at, updated_at)
-		VALUES ($1, $2, $3)
-	`, category, time.Now(), time.Now())
+		INSERT INTO category_articles (category, slug, description, parent_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, req.Category, categorySlug, req.Description, parentID, time.Now(), time.Now())
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -76,6 +159,8 @@ func CreateCategoryArticle(c *gin.Context) {
 		return
 	}
 
+	invalidateArticleCache()
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -90,8 +175,6 @@ func UpdateCategoryArticle(c *gin.Context) {
 		return
 	}
 
-	category := c.PostForm("category")
-
 	var req model.CategoryArticleRequest
 	if err := c.ShouldBind(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -119,23 +202,187 @@ func UpdateCategoryArticle(c *gin.Context) {
 		return
 	}
 
+	categorySlug, parentID, ok := categoryArticleInput(c, req, id)
+	if !ok {
+		return
+	}
+
 	// Update data
 	_, err = config.DB.Exec(`
 		UPDATE category_articles
-		SET category = $1, updated_at = $2
-		WHERE id = $3
-	`, category, time.Now(), id)
+		SET category = $1, slug = $2, description = $3, parent_id = $4, updated_at = $5
+		WHERE id = $6
+	`, req.Category, categorySlug, req.Description, parentID, time.Now(), id)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
 		return
 	}
 
+	invalidateArticleCache()
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data updated successfully",
 	})
 }
 
+// get pohon kategori artikel beserta jumlah artikel
+func GetCategoryArticleTree(c *gin.Context) {
+	rows, err := config.DB.Query(`
+		SELECT c.id, c.parent_id, c.category, c.slug, c.description, c.created_at, c.updated_at, COUNT(a.id)
+		FROM category_articles c
+		LEFT JOIN articles a ON a.category_id = c.id
+		GROUP BY c.id
+		ORDER BY c.category ASC
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	var nodes []model.CategoryArticleTree
+	for rows.Next() {
+		var node model.CategoryArticleTree
+		var parentID sql.NullInt64
+		if err := rows.Scan(&node.Id, &parentID, &node.Category, &node.Slug, &node.Description, &node.CreatedAt, &node.UpdatedAt, &node.ArticleCount); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if parentID.Valid {
+			id := int(parentID.Int64)
+			node.ParentId = &id
+		}
+		nodes = append(nodes, node)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": buildCategoryTree(nodes)})
+}
+
+// susun kategori flat menjadi tree berdasarkan parent_id
+func buildCategoryTree(nodes []model.CategoryArticleTree) []model.CategoryArticleTree {
+	children := make(map[int][]model.CategoryArticleTree)
+	known := make(map[int]bool)
+	for _, node := range nodes {
+		known[node.Id] = true
+	}
+
+	var roots []model.CategoryArticleTree
+	for _, node := range nodes {
+		if node.ParentId != nil && known[*node.ParentId] {
+			children[*node.ParentId] = append(children[*node.ParentId], node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	visited := make(map[int]bool)
+	var attach func(list []model.CategoryArticleTree) []model.CategoryArticleTree
+	attach = func(list []model.CategoryArticleTree) []model.CategoryArticleTree {
+		result := []model.CategoryArticleTree{}
+		for _, node := range list {
+			// jaga-jaga jika data lama punya siklus
+			if visited[node.Id] {
+				continue
+			}
+			visited[node.Id] = true
+
+			node.Children = attach(children[node.Id])
+			node.TotalArticles = node.ArticleCount
+			for _, child := range node.Children {
+				node.TotalArticles += child.TotalArticles
+			}
+			result = append(result, node)
+		}
+		return result
+	}
+
+	return attach(roots)
+}
+
+// get artikel dalam kategori (id atau slug) termasuk sub kategorinya
+func GetCategoryArticles(c *gin.Context) {
+	format, ok := articleOutputFormat(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected html, markdown or plain"})
+		return
+	}
+
+	categoryID, categoryName, err := resolveArticleCategory(c.Param("slug"))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	categoryIDs, err := categoryWithDescendants(categoryID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT
+			a.id, a.title, a.slug, a.description, a.content_format, a.thumbnail, a.views,
+			a.created_at, a.updated_at,
+			u.name AS user_name,
+			c.category AS category_name,
+			(SELECT COUNT(*) FROM article_comments cm WHERE cm.article_id = a.id AND cm.status = 'approved') AS comment_count
+		FROM articles a
+		JOIN users u ON a.user_id = u.id
+		JOIN category_articles c ON a.category_id = c.id
+		WHERE a.category_id = ANY($1)
+		ORDER BY a.created_at DESC
+	`, pq.Array(categoryIDs))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	articles := []model.ResponseArticle{}
+	for rows.Next() {
+		var art model.ResponseArticle
+		if err := rows.Scan(
+			&art.Id,
+			&art.Title,
+			&art.Slug,
+			&art.Description,
+			&art.ContentFormat,
+			&art.Thumbnail,
+			&art.Views,
+			&art.CreatedAt,
+			&art.UpdatedAt,
+			&art.User,
+			&art.Category,
+			&art.CommentCount,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		articles = append(articles, art)
+	}
+
+	refs := make([]*model.ResponseArticle, len(articles))
+	for i := range articles {
+		refs[i] = &articles[i]
+	}
+	if err := translateArticles(requestLocale(c), refs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	for _, art := range refs {
+		renderArticle(art, format)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     articles,
+		"category": gin.H{"id": categoryID, "category": categoryName, "category_ids": categoryIDs},
+	})
+}
+
 // delete category article
 func DeleteCategoryArticle(c *gin.Context) {
 	idParam := c.Param("id")
@@ -159,17 +406,106 @@ func DeleteCategoryArticle(c *gin.Context) {
 		return
 	}
 
-	// Hapus data
-	_, err = config.DB.Exec(`DELETE FROM category_articles WHERE id = $1`, id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "Failed to delete data",
-			"detail": err.Error(),
-		})
+	reassigned, ok := deleteCategory(c, id, categoryRelation{
+		categoryTable: "category_articles",
+		childTable:    "articles",
+		reassignSQL:   `UPDATE articles SET category_id = $1, updated_at = NOW() WHERE category_id = $2`,
+		// sub kategori naik satu tingkat ke parent kategori yang dihapus
+		beforeDeleteSQL: `
+			UPDATE category_articles
+			SET parent_id = (SELECT parent_id FROM category_articles WHERE id = $1), updated_at = NOW()
+			WHERE parent_id = $1`,
+	})
+	if !ok {
 		return
 	}
 
+	invalidateArticleCache()
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Data deleted successfully",
+		"message":    "Data deleted successfully",
+		"reassigned": reassigned,
 	})
 }
+
+// relasi kategori -> data anak untuk pengecekan sebelum hapus
+type categoryRelation struct {
+	categoryTable string // category_articles / category_faqs
+	childTable    string // articles / faqs
+	reassignSQL   string // $1 kategori tujuan, $2 kategori yang dihapus
+
+	beforeDeleteSQL string // opsional, $1 kategori yang dihapus
+}
+
+// hapus kategori: jika masih dipakai, pindahkan ke ?reassign_to= atau tolak dengan 409.
+// Return jumlah data yang dipindahkan, false jika response error sudah dikirim.
+func deleteCategory(c *gin.Context, id int, rel categoryRelation) (int, bool) {
+	var dependents int
+	err := config.DB.QueryRow(`SELECT COUNT(*) FROM `+rel.childTable+` WHERE category_id = $1`, id).Scan(&dependents)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return 0, false
+	}
+
+	targetID := 0
+	if reassignParam := c.Query("reassign_to"); reassignParam != "" {
+		targetID, err = strconv.Atoi(reassignParam)
+		if err != nil || targetID == id {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reassign_to"})
+			return 0, false
+		}
+
+		var exists int
+		err = config.DB.QueryRow(`SELECT 1 FROM `+rel.categoryTable+` WHERE id = $1`, targetID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Target category not found"})
+			return 0, false
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return 0, false
+		}
+	} else if dependents > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":        "Category is still in use, pass ?reassign_to= to move them to another category",
+			rel.childTable: dependents,
+		})
+		return 0, false
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return 0, false
+	}
+	defer tx.Rollback()
+
+	reassigned := 0
+	if targetID != 0 {
+		result, err := tx.Exec(rel.reassignSQL, targetID, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+			return 0, false
+		}
+		rowsAffected, _ := result.RowsAffected()
+		reassigned = int(rowsAffected)
+	}
+
+	if rel.beforeDeleteSQL != "" {
+		if _, err := tx.Exec(rel.beforeDeleteSQL, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+			return 0, false
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM `+rel.categoryTable+` WHERE id = $1`, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		return 0, false
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		return 0, false
+	}
+
+	return reassigned, true
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/gibranfajar/backend-codetech/config"
@@ -19,7 +20,7 @@ import (
 func GetAllCategoryFaq(c *gin.Context) {
 	var categoryFaqs []model.CategoryFaq
 
-	rows, err := config.DB.Query("SELECT id, category, description, icon, created_at, updated_at FROM category_faqs")
+	rows, err := config.DB.Query("SELECT id, category, description, icon, position, created_at, updated_at FROM category_faqs ORDER BY position ASC, id ASC")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
 		return
@@ -32,7 +33,7 @@ func GetAllCategoryFaq(c *gin.Context) {
 
 	for rows.Next() {
 		var categoryFaq model.CategoryFaq
-		if err := rows.Scan(&categoryFaq.Id, &categoryFaq.Category, &categoryFaq.Description, &categoryFaq.Icon, &categoryFaq.CreatedAt, &categoryFaq.UpdatedAt); err != nil {
+		if err := rows.Scan(&categoryFaq.Id, &categoryFaq.Category, &categoryFaq.Description, &categoryFaq.Icon, &categoryFaq.Position, &categoryFaq.CreatedAt, &categoryFaq.UpdatedAt); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
 			return
 		}
@@ -87,8 +88,8 @@ func CreateCategoryFaq(c *gin.Context) {
 
 	// Simpan ke database (PostgreSQL style)
 	_, err = config.DB.Exec(`
-		INSERT INTO category_faqs (category, description, icon, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO category_faqs (category, description, icon, position, created_at, updated_at)
+		VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position), 0) + 1 FROM category_faqs), $4, $5)
 	`, category, description, icon, time.Now(), time.Now())
 
 	if err != nil {
@@ -177,11 +178,15 @@ func UpdateCategoryFaq(c *gin.Context) {
 
 // delete data
 func DeleteCategoryFaq(c *gin.Context) {
-	id := c.Param("id")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
 
 	// Ambil data lama untuk dapatkan icon lama
 	var oldIcon string
-	err := config.DB.QueryRow("SELECT icon FROM category_faqs WHERE id = $1", id).Scan(&oldIcon)
+	err = config.DB.QueryRow("SELECT icon FROM category_faqs WHERE id = $1", id).Scan(&oldIcon)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
 		return
@@ -193,33 +198,31 @@ func DeleteCategoryFaq(c *gin.Context) {
 		return
 	}
 
-	// Hapus file lama jika ada
-	if oldIcon != "" {
-		imageFile := filepath.Base(oldIcon)
-		imagePath := filepath.Join("uploads", imageFile)
-
-		if _, err := os.Stat(imagePath); err == nil {
-			if err := os.Remove(imagePath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":  "Failed to delete image file",
-					"detail": err.Error(),
-				})
-				return
-			}
-		}
-	}
-
-	// Hapus data dari database
-	_, err = config.DB.Exec("DELETE FROM category_faqs WHERE id = $1", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "Failed to delete data",
-			"detail": err.Error(),
-		})
+	// faq yang dipindah ditaruh setelah faq milik kategori tujuan
+	reassigned, ok := deleteCategory(c, id, categoryRelation{
+		categoryTable: "category_faqs",
+		childTable:    "faqs",
+		reassignSQL: `
+			UPDATE faqs
+			SET category_id = $1,
+				position = position + (SELECT COALESCE(MAX(position), 0) FROM faqs WHERE category_id = $1),
+				updated_at = NOW()
+			WHERE category_id = $2`,
+	})
+	if !ok {
 		return
 	}
 
+	// Hapus file icon setelah data terhapus
+	removeUploadedFile(oldIcon)
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Data deleted successfully",
+		"message":    "Data deleted successfully",
+		"reassigned": reassigned,
 	})
 }
+
+// ubah urutan kategori faq
+func ReorderCategoryFaqs(c *gin.Context) {
+	reorderPositions(c, "category_faqs", "", 0)
+}
@@ -0,0 +1,345 @@
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// get komentar yang sudah disetujui untuk artikel (bertingkat)
+func GetArticleComments(c *gin.Context) {
+	slugParam := c.Param("slug")
+
+	var articleID int
+	err := config.DB.QueryRow(`SELECT id FROM articles WHERE slug = $1`, slugParam).Scan(&articleID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, parent_id, name, body, created_at
+		FROM article_comments
+		WHERE article_id = $1 AND status = $2
+		ORDER BY created_at ASC
+	`, articleID, model.CommentStatusApproved)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	var comments []model.CommentResponse
+	for rows.Next() {
+		var comment model.CommentResponse
+		var parentID sql.NullInt64
+		if err := rows.Scan(&comment.Id, &parentID, &comment.Name, &comment.Body, &comment.CreatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if parentID.Valid {
+			id := int(parentID.Int64)
+			comment.ParentId = &id
+		}
+		comments = append(comments, comment)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  buildCommentTree(comments),
+		"total": len(comments),
+	})
+}
+
+// susun komentar flat menjadi tree berdasarkan parent_id
+func buildCommentTree(comments []model.CommentResponse) []model.CommentResponse {
+	children := make(map[int][]model.CommentResponse)
+	known := make(map[int]bool)
+	for _, comment := range comments {
+		known[comment.Id] = true
+	}
+
+	var roots []model.CommentResponse
+	for _, comment := range comments {
+		// balasan dari komentar yang tidak tampil dijadikan root
+		if comment.ParentId != nil && known[*comment.ParentId] {
+			children[*comment.ParentId] = append(children[*comment.ParentId], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	var attach func(list []model.CommentResponse) []model.CommentResponse
+	attach = func(list []model.CommentResponse) []model.CommentResponse {
+		for i := range list {
+			list[i].Replies = attach(children[list[i].Id])
+		}
+		if list == nil {
+			return []model.CommentResponse{}
+		}
+		return list
+	}
+
+	return attach(roots)
+}
+
+// kirim komentar baru (publik), masuk antrian moderasi
+func CreateComment(c *gin.Context) {
+	slugParam := c.Param("slug")
+
+	var req model.CommentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// honeypot: field "website" disembunyikan di form, hanya bot yang mengisinya
+	if c.PostForm("website") != "" {
+		c.JSON(http.StatusCreated, gin.H{"message": "Comment submitted and awaiting moderation"})
+		return
+	}
+
+	var articleID int
+	err := config.DB.QueryRow(`SELECT id FROM articles WHERE slug = $1`, slugParam).Scan(&articleID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	// balasan hanya boleh ke komentar approved di artikel yang sama
+	var parentID interface{}
+	if req.ParentId != 0 {
+		var parentArticleID int
+		var parentStatus string
+		err := config.DB.QueryRow(`
+			SELECT article_id, status FROM article_comments WHERE id = $1
+		`, req.ParentId).Scan(&parentArticleID, &parentStatus)
+		if err == sql.ErrNoRows || (err == nil && (parentArticleID != articleID || parentStatus != model.CommentStatusApproved)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent comment"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return
+		}
+		parentID = req.ParentId
+	}
+
+	// komentar disimpan sebagai teks biasa
+	name := strings.TrimSpace(utils.HTMLToPlainText(req.Name))
+	body := utils.HTMLToPlainText(req.Body)
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if name == "" || len(body) < 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment body is empty"})
+		return
+	}
+
+	// heuristik spam: isi, duplikat, dan flood dari email yang sama; isi dinilai dari
+	// input asli karena markup link (<a, [url) sudah hilang setelah dibersihkan
+	score := utils.SpamScore(req.Name + " " + req.Body)
+
+	var duplicates, recent int
+	err = config.DB.QueryRow(`
+		SELECT
+			COUNT(*) FILTER (WHERE body = $2 AND created_at > NOW() - INTERVAL '24 hours'),
+			COUNT(*) FILTER (WHERE created_at > NOW() - INTERVAL '10 minutes')
+		FROM article_comments
+		WHERE email = $1
+	`, email, body).Scan(&duplicates, &recent)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if duplicates > 0 {
+		score += 3
+	}
+	if recent >= 3 {
+		score += 2
+	}
+
+	// email komentator tidak diverifikasi, jadi semua komentar tetap dimoderasi
+	status := model.CommentStatusPending
+	if score >= utils.SpamThreshold {
+		status = model.CommentStatusSpam
+	}
+
+	_, err = config.DB.Exec(`
+		INSERT INTO article_comments (article_id, parent_id, name, email, body, status, spam_score, ip_hash, user_agent, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, articleID, parentID, name, email, body, status, score,
+		analytics.VisitorHash(c.ClientIP(), ""), c.Request.UserAgent(), time.Now(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	// status spam tidak diberitahukan ke pengirim
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment submitted and awaiting moderation"})
+}
+
+// get antrian moderasi komentar (admin)
+func GetAllComments(c *gin.Context) {
+	status := c.DefaultQuery("status", model.CommentStatusPending)
+
+	query := `
+		SELECT
+			cm.id, cm.article_id, cm.parent_id, cm.name, cm.email, cm.body, cm.status, cm.spam_score,
+			cm.created_at, cm.updated_at, a.title, a.slug
+		FROM article_comments cm
+		JOIN articles a ON a.id = cm.article_id
+		WHERE 1 = 1`
+	var args []interface{}
+
+	switch status {
+	case "all":
+	case model.CommentStatusPending, model.CommentStatusApproved, model.CommentStatusRejected, model.CommentStatusSpam:
+		args = append(args, status)
+		query += fmt.Sprintf(" AND cm.status = $%d", len(args))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	if articleParam := c.Query("article_id"); articleParam != "" {
+		articleID, err := strconv.Atoi(articleParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article_id"})
+			return
+		}
+		args = append(args, articleID)
+		query += fmt.Sprintf(" AND cm.article_id = $%d", len(args))
+	}
+
+	query += " ORDER BY cm.created_at ASC"
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	comments := []model.CommentModeration{}
+	for rows.Next() {
+		var comment model.CommentModeration
+		var parentID sql.NullInt64
+		if err := rows.Scan(
+			&comment.Id,
+			&comment.ArticleId,
+			&parentID,
+			&comment.Name,
+			&comment.Email,
+			&comment.Body,
+			&comment.Status,
+			&comment.SpamScore,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+			&comment.ArticleTitle,
+			&comment.ArticleSlug,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if parentID.Valid {
+			id := int(parentID.Int64)
+			comment.ParentId = &id
+		}
+		comments = append(comments, comment)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comments})
+}
+
+// ubah status moderasi komentar (approve / reject / spam)
+func UpdateCommentStatus(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req model.CommentStatusRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	result, err := config.DB.Exec(`
+		UPDATE article_comments SET status = $1, updated_at = $2 WHERE id = $3
+	`, req.Status, time.Now(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	invalidateArticleCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
+// delete komentar (beserta balasannya)
+func DeleteComment(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	result, err := config.DB.Exec(`DELETE FROM article_comments WHERE id = $1`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	invalidateArticleCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data deleted successfully"})
+}
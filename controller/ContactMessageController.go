@@ -0,0 +1,291 @@
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/mailer"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// kirim pesan dari form kontak (publik)
+func CreateContactMessage(c *gin.Context) {
+	var req model.ContactMessageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	phone := strings.TrimSpace(req.Phone)
+	if phone != "" && !phonePattern.MatchString(phone) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []string{"Phone is invalid"}})
+		return
+	}
+
+	// honeypot: field "website" disembunyikan di form, hanya bot yang mengisinya
+	if c.PostForm("website") != "" {
+		c.JSON(http.StatusCreated, gin.H{"message": "Message sent successfully"})
+		return
+	}
+
+	// disimpan sebagai teks biasa
+	msg := model.ContactMessage{
+		Name:    strings.TrimSpace(utils.HTMLToPlainText(req.Name)),
+		Email:   strings.ToLower(strings.TrimSpace(req.Email)),
+		Phone:   phone,
+		Subject: strings.TrimSpace(utils.HTMLToPlainText(req.Subject)),
+		Message: utils.HTMLToPlainText(req.Message),
+	}
+	if msg.Name == "" || len(strings.TrimSpace(msg.Message)) < 10 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message body is empty"})
+		return
+	}
+
+	err := config.DB.QueryRow(`
+		INSERT INTO contact_messages (name, email, phone, subject, message, ip_hash, user_agent, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, msg.Name, msg.Email, msg.Phone, msg.Subject, msg.Message,
+		analytics.VisitorHash(c.ClientIP(), ""), c.Request.UserAgent(), time.Now(), time.Now()).Scan(&msg.Id, &msg.CreatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	notifyContactMessage(msg)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Message sent successfully"})
+}
+
+// antrekan notifikasi ke email perusahaan di tabel contacts, pengiriman + retry
+// diurus outbox mailer (status terkirim dibaca dari baris outbox-nya); gagal
+// mengantre tidak menggagalkan pesan yang sudah tersimpan
+func notifyContactMessage(msg model.ContactMessage) {
+	var to string
+	err := config.DB.QueryRow(`SELECT email FROM contacts ORDER BY id ASC LIMIT 1`).Scan(&to)
+	if err != nil {
+		log.Printf("contact message %d: no notification address: %v", msg.Id, err)
+		return
+	}
+
+	outboxID, err := mailer.Enqueue("contact_message", to, msg.Email, mailer.Data{
+		"Name":    msg.Name,
+		"Email":   msg.Email,
+		"Phone":   msg.Phone,
+		"Subject": msg.Subject,
+		"Message": msg.Message,
+		"SentAt":  msg.CreatedAt.Format(time.RFC1123Z),
+	})
+	if err != nil {
+		log.Printf("contact message %d: failed to queue notification: %v", msg.Id, err)
+		return
+	}
+
+	_, _ = config.DB.Exec(`UPDATE contact_messages SET notification_id = $1 WHERE id = $2`, outboxID, msg.Id)
+}
+
+// notified_at & status notifikasi berasal dari baris mail_outbox, dibungkus subquery
+// agar filter / urutan di handler tetap memakai nama kolom contact_messages
+const contactMessageSelect = `
+	SELECT id, name, email, phone, subject, message, read_at, archived_at, notified_at, notification_status, created_at, updated_at
+	FROM (
+		SELECT m.*, o.sent_at AS notified_at, COALESCE(o.status, '') AS notification_status
+		FROM contact_messages m
+		LEFT JOIN mail_outbox o ON o.id = m.notification_id
+	) AS contact_messages`
+
+func scanContactMessage(scanner interface{ Scan(...interface{}) error }) (model.ContactMessage, error) {
+	var msg model.ContactMessage
+	var readAt, archivedAt, notifiedAt sql.NullTime
+	err := scanner.Scan(
+		&msg.Id, &msg.Name, &msg.Email, &msg.Phone, &msg.Subject, &msg.Message,
+		&readAt, &archivedAt, &notifiedAt, &msg.NotificationStatus, &msg.CreatedAt, &msg.UpdatedAt,
+	)
+	if readAt.Valid {
+		msg.ReadAt = &readAt.Time
+		msg.Read = true
+	}
+	if archivedAt.Valid {
+		msg.ArchivedAt = &archivedAt.Time
+		msg.Archived = true
+	}
+	if notifiedAt.Valid {
+		msg.NotifiedAt = &notifiedAt.Time
+	}
+	return msg, err
+}
+
+// inbox pesan kontak (admin), ?state=inbox|unread|read|archived|all
+func GetAllContactMessages(c *gin.Context) {
+	query := contactMessageSelect
+	switch c.DefaultQuery("state", "inbox") {
+	case "inbox":
+		query += ` WHERE archived_at IS NULL`
+	case "unread":
+		query += ` WHERE archived_at IS NULL AND read_at IS NULL`
+	case "read":
+		query += ` WHERE archived_at IS NULL AND read_at IS NOT NULL`
+	case "archived":
+		query += ` WHERE archived_at IS NOT NULL`
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state"})
+		return
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := config.DB.Query(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	messages := []model.ContactMessage{}
+	for rows.Next() {
+		msg, err := scanContactMessage(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		messages = append(messages, msg)
+	}
+
+	var unread int
+	if err := config.DB.QueryRow(`
+		SELECT COUNT(*) FROM contact_messages WHERE read_at IS NULL AND archived_at IS NULL
+	`).Scan(&unread); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": messages, "unread": unread})
+}
+
+// detail pesan, otomatis ditandai sudah dibaca
+func GetContactMessage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	_, err = config.DB.Exec(`
+		UPDATE contact_messages SET read_at = $1, updated_at = $1 WHERE id = $2 AND read_at IS NULL
+	`, time.Now(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	msg, err := scanContactMessage(config.DB.QueryRow(contactMessageSelect+` WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": msg})
+}
+
+// set / hapus penanda waktu (read_at / archived_at) dari form value=true|false
+func setContactMessageFlag(c *gin.Context, column string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req model.ContactMessageFlagRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	var value interface{}
+	if *req.Value {
+		value = time.Now()
+	}
+
+	// kolom berasal dari handler (read_at / archived_at), bukan dari input
+	result, err := config.DB.Exec(`
+		UPDATE contact_messages SET `+column+` = $1, updated_at = $2 WHERE id = $3
+	`, value, time.Now(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
+// tandai sudah / belum dibaca
+func MarkContactMessageRead(c *gin.Context) {
+	setContactMessageFlag(c, "read_at")
+}
+
+// arsipkan / kembalikan ke inbox
+func ArchiveContactMessage(c *gin.Context) {
+	setContactMessageFlag(c, "archived_at")
+}
+
+// delete pesan kontak
+func DeleteContactMessage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	result, err := config.DB.Exec(`DELETE FROM contact_messages WHERE id = $1`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data deleted successfully"})
+}
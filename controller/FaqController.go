@@ -11,67 +11,244 @@ import (
 	"github.com/gibranfajar/backend-codetech/model"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
 )
 
-// get all data
-func GetAllFaq(c *gin.Context) {
-	var faqs []model.FaqResponse
-
+// ambil semua faq urut kategori lalu posisi, sudah diterjemahkan sesuai locale
+func loadFaqs(locale string) ([]model.FaqResponse, error) {
 	rows, err := config.DB.Query(`
 		SELECT
 			f.id,
 			f.question,
 			f.answer,
+			f.category_id,
 			c.category,
+			f.position,
 			f.created_at,
 			f.updated_at
 		FROM faqs f
 		JOIN category_faqs c ON f.category_id = c.id
-		ORDER BY f.created_at DESC
+		ORDER BY c.position ASC, c.id ASC, f.position ASC, f.id ASC
 	`)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "Failed to fetch data",
-			"detail": err.Error(),
-		})
-		return
+		return nil, err
 	}
 	defer rows.Close()
 
+	faqs := []model.FaqResponse{}
 	for rows.Next() {
 		var faq model.FaqResponse
 		if err := rows.Scan(
 			&faq.Id,
 			&faq.Question,
 			&faq.Answer,
+			&faq.CategoryId,
 			&faq.Category,
+			&faq.Position,
 			&faq.CreatedAt,
 			&faq.UpdatedAt,
 		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":  "Failed to parse data",
-				"detail": err.Error(),
-			})
-			return
+			return nil, err
 		}
 		faqs = append(faqs, faq)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(faqs))
+	for i := range faqs {
+		ids[i] = faqs[i].Id
+	}
+	translations, err := loadTranslations("faqs", locale, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range faqs {
+		translateField(translations[faqs[i].Id], "question", &faqs[i].Question)
+		translateField(translations[faqs[i].Id], "answer", &faqs[i].Answer)
+	}
 
-	// Cek jika tidak ada data
-	if len(faqs) == 0 {
-		c.JSON(http.StatusOK, gin.H{"data": []interface{}{}})
+	return faqs, nil
+}
+
+// get all data
+func GetAllFaq(c *gin.Context) {
+	faqs, err := loadFaqs(requestLocale(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to fetch data",
+			"detail": err.Error(),
+		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": faqs})
 }
 
+// get faq dikelompokkan per kategori + JSON-LD FAQPage
+func GetGroupedFaqs(c *gin.Context) {
+	rows, err := config.DB.Query(`
+		SELECT id, category, description, icon, position, created_at, updated_at
+		FROM category_faqs
+		ORDER BY position ASC, id ASC
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	groups := []model.FaqGroup{}
+	index := map[int]int{}
+	for rows.Next() {
+		var group model.FaqGroup
+		if err := rows.Scan(&group.Id, &group.Category, &group.Description, &group.Icon, &group.Position, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		group.Faqs = []model.FaqResponse{}
+		index[group.Id] = len(groups)
+		groups = append(groups, group)
+	}
+
+	faqs, err := loadFaqs(requestLocale(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	for _, faq := range faqs {
+		if i, ok := index[faq.CategoryId]; ok {
+			groups[i].Faqs = append(groups[i].Faqs, faq)
+		}
+	}
+
+	// kategori kosong tidak ditampilkan kecuali diminta
+	if c.Query("include_empty") != "1" {
+		filtered := []model.FaqGroup{}
+		for _, group := range groups {
+			if len(group.Faqs) > 0 {
+				filtered = append(filtered, group)
+			}
+		}
+		groups = filtered
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    groups,
+		"json_ld": faqPageJSONLD(faqs),
+	})
+}
+
+// pastikan kategori faq ada sebelum dipakai sebagai foreign key
+func faqCategoryExists(id int) (bool, error) {
+	var exists int
+	err := config.DB.QueryRow(`SELECT 1 FROM category_faqs WHERE id = $1`, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// simpan urutan baru: posisi = index di daftar ids (mulai dari 1), baris yang tidak
+// disebut digeser ke belakangnya dengan urutan lama.
+// scopeID membatasi ke satu parent (contoh product_id dari path), 0 = pakai category_id di body.
+func reorderPositions(c *gin.Context, table, scopeColumn string, scopeID int) {
+	var req model.ReorderRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	seen := map[int]bool{}
+	for _, id := range req.Ids {
+		if seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Duplicate id %d", id)})
+			return
+		}
+		seen[id] = true
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	defer tx.Rollback()
+
+	if scopeID == 0 {
+		scopeID = req.CategoryId
+	}
+
+	now := time.Now()
+	for i, id := range req.Ids {
+		query := `UPDATE ` + table + ` SET position = $1, updated_at = $2 WHERE id = $3`
+		args := []interface{}{i + 1, now, id}
+		if scopeColumn != "" && scopeID != 0 {
+			query += ` AND ` + scopeColumn + ` = $4`
+			args = append(args, scopeID)
+		}
+
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+			return
+		}
+		if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid id %d", id)})
+			return
+		}
+	}
+
+	// reorder sebagian: sisa baris mulai dari len(ids)+1 agar posisinya tidak bentrok,
+	// tanpa scope posisi dihitung per scopeColumn (contoh per kategori faq)
+	scope, partition := "", ""
+	args := []interface{}{len(req.Ids), now, pq.Array(req.Ids)}
+	if scopeColumn != "" && scopeID != 0 {
+		scope = ` AND ` + scopeColumn + ` = $4`
+		args = append(args, scopeID)
+	} else if scopeColumn != "" {
+		partition = `PARTITION BY ` + scopeColumn + ` `
+	}
+	_, err = tx.Exec(`
+		UPDATE `+table+` t SET position = o.rn + $1, updated_at = $2
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (`+partition+`ORDER BY position, id) AS rn
+			FROM `+table+`
+			WHERE NOT (id = ANY($3))`+scope+`
+		) o
+		WHERE t.id = o.id
+	`, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data reordered successfully"})
+}
+
+// ubah urutan faq (opsional dalam satu category_id)
+func ReorderFaqs(c *gin.Context) {
+	reorderPositions(c, "faqs", "category_id", 0)
+}
+
 // create data
 func CreateFaq(c *gin.Context) {
-	question := c.PostForm("question")
-	answer := c.PostForm("answer")
-	categoryId := c.PostForm("category_id")
-
 	var req model.FaqRequest
 	if err := c.ShouldBind(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -88,11 +265,20 @@ func CreateFaq(c *gin.Context) {
 		return
 	}
 
-	// Insert ke database
-	_, err := config.DB.Exec(`
-		INSERT INTO faqs (question, answer, category_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`, question, answer, categoryId, time.Now(), time.Now())
+	exists, err := faqCategoryExists(req.CategoryId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	} else if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id"})
+		return
+	}
+
+	// Insert ke database, posisi di akhir kategori
+	_, err = config.DB.Exec(`
+		INSERT INTO faqs (question, answer, category_id, position, created_at, updated_at)
+		VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position), 0) + 1 FROM faqs WHERE category_id = $3), $4, $5)
+	`, req.Question, req.Answer, req.CategoryId, time.Now(), time.Now())
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -134,7 +320,7 @@ func UpdateFaq(c *gin.Context) {
 
 	// Check data exist
 	var faq model.Faq
-	err = config.DB.QueryRow("SELECT id FROM faqs WHERE id = $1", id).Scan(&faq.Id)
+	err = config.DB.QueryRow("SELECT id, category_id FROM faqs WHERE id = $1", id).Scan(&faq.Id, &faq.CategoryId)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
@@ -143,16 +329,27 @@ func UpdateFaq(c *gin.Context) {
 		return
 	}
 
-	question := c.PostForm("question")
-	answer := c.PostForm("answer")
-	categoryId := c.PostForm("category_id")
+	exists, err := faqCategoryExists(req.CategoryId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	} else if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id"})
+		return
+	}
+
+	// pindah kategori: taruh di akhir kategori baru
+	position := "position"
+	if req.CategoryId != faq.CategoryId {
+		position = "(SELECT COALESCE(MAX(position), 0) + 1 FROM faqs WHERE category_id = $3)"
+	}
 
 	// Update database
 	_, err = config.DB.Exec(`
 		UPDATE faqs
-		SET question = $1, answer = $2, category_id = $3, updated_at = $4
+		SET question = $1, answer = $2, category_id = $3, position = `+position+`, updated_at = $4
 		WHERE id = $5
-	`, question, answer, categoryId, time.Now(), id)
+	`, req.Question, req.Answer, req.CategoryId, time.Now(), id)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
@@ -191,6 +388,8 @@ func DeleteFaq(c *gin.Context) {
 		return
 	}
 
+	deleteTranslations("faqs", id)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
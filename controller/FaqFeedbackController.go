@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// kirim vote membantu / tidak membantu untuk faq (publik)
+func VoteFaq(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req model.FaqVoteRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	var exists int
+	err = config.DB.QueryRow(`SELECT 1 FROM faqs WHERE id = $1`, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	// bot tidak dihitung, tetap dibalas sukses
+	if analytics.IsBot(c.Request.UserAgent()) {
+		c.JSON(http.StatusOK, gin.H{"message": "Thank you for your feedback"})
+		return
+	}
+
+	// satu vote per pengunjung, vote ulang mengganti vote sebelumnya
+	feedback := utils.HTMLToPlainText(req.Feedback)
+	_, err = config.DB.Exec(`
+		INSERT INTO faq_votes (faq_id, helpful, feedback, visitor_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (faq_id, visitor_hash)
+		DO UPDATE SET helpful = EXCLUDED.helpful, feedback = EXCLUDED.feedback, updated_at = EXCLUDED.updated_at
+	`, id, *req.Helpful, feedback, analytics.VisitorHash(c.ClientIP(), c.Request.UserAgent()), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Thank you for your feedback"})
+}
+
+// rapikan kata kunci pencarian: huruf kecil dan spasi tunggal
+func normalizeSearchQuery(q string) string {
+	q = strings.Join(strings.Fields(strings.ToLower(q)), " ")
+	// dipotong per karakter (VARCHAR(255)) agar tidak memotong UTF-8 di tengah
+	if runes := []rune(q); len(runes) > 255 {
+		q = strings.TrimSpace(string(runes[:255]))
+	}
+	return q
+}
+
+// cari faq (pertanyaan + jawaban), pencarian tanpa hasil dicatat
+func SearchFaqs(c *gin.Context) {
+	q := normalizeSearchQuery(c.Query("q"))
+	if len([]rune(q)) < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query must be at least 2 characters"})
+		return
+	}
+
+	faqs, err := loadFaqs(requestLocale(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+
+	// semua kata harus muncul di pertanyaan atau jawaban
+	terms := strings.Fields(q)
+	results := []model.FaqResponse{}
+	for _, faq := range faqs {
+		text := strings.ToLower(faq.Question + " " + utils.HTMLToPlainText(faq.Answer))
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			results = append(results, faq)
+		}
+	}
+
+	if len(results) == 0 && !analytics.IsBot(c.Request.UserAgent()) {
+		_, err := config.DB.Exec(`
+			INSERT INTO faq_search_misses (query, count, first_searched_at, last_searched_at)
+			VALUES ($1, 1, $2, $2)
+			ON CONFLICT (query)
+			DO UPDATE SET count = faq_search_misses.count + 1, last_searched_at = EXCLUDED.last_searched_at
+		`, q, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results, "total": len(results)})
+}
+
+// laporan admin: faq paling tidak membantu + pencarian tanpa hasil
+func GetFaqHelpfulnessReport(c *gin.Context) {
+	limit, ok := parseLimit(c, 20)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	minVotes, err := strconv.Atoi(c.DefaultQuery("min_votes", "3"))
+	if err != nil || minVotes < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_votes"})
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT
+			f.id, f.question, c.category,
+			COUNT(*) FILTER (WHERE v.helpful) AS helpful,
+			COUNT(*) FILTER (WHERE NOT v.helpful) AS not_helpful,
+			COUNT(*) AS total_votes,
+			COUNT(*) FILTER (WHERE v.feedback <> '') AS feedback_count
+		FROM faq_votes v
+		JOIN faqs f ON f.id = v.faq_id
+		JOIN category_faqs c ON c.id = f.category_id
+		GROUP BY f.id, f.question, c.category
+		HAVING COUNT(*) >= $1
+		ORDER BY
+			COUNT(*) FILTER (WHERE NOT v.helpful)::float / COUNT(*) DESC,
+			COUNT(*) FILTER (WHERE NOT v.helpful) DESC,
+			f.id ASC
+		LIMIT $2
+	`, minVotes, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	report := []model.FaqHelpfulness{}
+	for rows.Next() {
+		var item model.FaqHelpfulness
+		if err := rows.Scan(&item.Id, &item.Question, &item.Category, &item.Helpful, &item.NotHelpful, &item.TotalVotes, &item.FeedbackCount); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if item.TotalVotes > 0 {
+			item.UnhelpfulRatio = float64(item.NotHelpful) / float64(item.TotalVotes)
+		}
+		report = append(report, item)
+	}
+
+	missRows, err := config.DB.Query(`
+		SELECT query, count, first_searched_at, last_searched_at
+		FROM faq_search_misses
+		ORDER BY count DESC, last_searched_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer missRows.Close()
+
+	misses := []model.FaqSearchMiss{}
+	for missRows.Next() {
+		var miss model.FaqSearchMiss
+		if err := missRows.Scan(&miss.Query, &miss.Count, &miss.FirstSearchedAt, &miss.LastSearchedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		misses = append(misses, miss)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":          report,
+		"search_misses": misses,
+		"min_votes":     minVotes,
+	})
+}
+
+// daftar feedback teks untuk satu faq (admin)
+func GetFaqFeedback(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, faq_id, helpful, feedback, created_at
+		FROM faq_votes
+		WHERE faq_id = $1 AND feedback <> ''
+		ORDER BY updated_at DESC
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	feedback := []model.FaqFeedback{}
+	for rows.Next() {
+		var item model.FaqFeedback
+		if err := rows.Scan(&item.Id, &item.FaqId, &item.Helpful, &item.Feedback, &item.CreatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		feedback = append(feedback, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": feedback})
+}
+
+// hapus catatan pencarian tanpa hasil (setelah faq baru ditambahkan)
+func DeleteFaqSearchMisses(c *gin.Context) {
+	query := `DELETE FROM faq_search_misses`
+	var args []interface{}
+	if q := c.Query("q"); q != "" {
+		query += ` WHERE query = $1`
+		args = append(args, normalizeSearchQuery(q))
+	}
+
+	if _, err := config.DB.Exec(query, args...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data deleted successfully"})
+}
@@ -0,0 +1,306 @@
+package controller
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	feedLimit         = 50
+	articlePathPrefix = "/articles/"
+)
+
+type feedData struct {
+	title        string
+	articles     []model.ResponseArticle
+	lastModified time.Time
+	etag         string
+}
+
+// ubah path relatif (/uploads/...) menjadi URL absolut berdasarkan config.APIURL
+func absoluteURL(path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return config.APIURL + "/" + strings.TrimLeft(path, "/")
+}
+
+func articleURL(articleSlug string) string {
+	return strings.TrimRight(config.SiteURL, "/") + articlePathPrefix + articleSlug
+}
+
+// cari kategori artikel dari id atau slug kategori
+func resolveArticleCategory(param string) (int, string, error) {
+	var id int
+	var name string
+
+	if n, err := strconv.Atoi(param); err == nil {
+		err := config.DB.QueryRow(`SELECT id, category FROM category_articles WHERE id = $1`, n).Scan(&id, &name)
+		return id, name, err
+	}
+
+	err := config.DB.QueryRow(`SELECT id, category FROM category_articles WHERE slug = $1`, param).Scan(&id, &name)
+	return id, name, err
+}
+
+// ambil artikel terbaru untuk feed (opsional per kategori via ?category=)
+func loadFeed(c *gin.Context, kind string) (*feedData, bool) {
+	feed := &feedData{title: config.SiteName}
+
+	query := `
+		SELECT
+			a.id, a.title, a.slug, a.description, a.content_format, a.thumbnail, a.views,
+			a.created_at, a.updated_at,
+			u.name AS user_name,
+			c.category AS category_name
+		FROM articles a
+		JOIN users u ON a.user_id = u.id
+		JOIN category_articles c ON a.category_id = c.id`
+	args := []interface{}{feedLimit}
+
+	categoryParam := c.Query("category")
+	if categoryParam != "" {
+		categoryID, categoryName, err := resolveArticleCategory(categoryParam)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return nil, false
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return nil, false
+		}
+		categoryIDs, err := categoryWithDescendants(categoryID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return nil, false
+		}
+		feed.title = config.SiteName + " - " + categoryName
+		args = append(args, pq.Array(categoryIDs))
+		query += ` WHERE a.category_id = ANY($2)`
+	}
+	query += ` ORDER BY a.created_at DESC LIMIT $1`
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return nil, false
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var art model.ResponseArticle
+		if err := rows.Scan(
+			&art.Id,
+			&art.Title,
+			&art.Slug,
+			&art.Description,
+			&art.ContentFormat,
+			&art.Thumbnail,
+			&art.Views,
+			&art.CreatedAt,
+			&art.UpdatedAt,
+			&art.User,
+			&art.Category,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return nil, false
+		}
+		renderArticle(&art, utils.ContentFormatHTML)
+		art.Thumbnail = absoluteURL(art.Thumbnail)
+
+		if art.UpdatedAt.After(feed.lastModified) {
+			feed.lastModified = art.UpdatedAt
+		}
+		feed.articles = append(feed.articles, art)
+	}
+
+	// HTTP date hanya presisi detik
+	feed.lastModified = feed.lastModified.UTC().Truncate(time.Second)
+
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%d|%d", kind, categoryParam, feed.lastModified.Unix(), len(feed.articles))))
+	feed.etag = `W/"` + hex.EncodeToString(sum[:]) + `"`
+
+	return feed, true
+}
+
+// set header cache dan cek conditional GET, return true jika 304 sudah dikirim
+func feedNotModified(c *gin.Context, feed *feedData) bool {
+	c.Header("ETag", feed.etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if !feed.lastModified.IsZero() {
+		c.Header("Last-Modified", feed.lastModified.Format(http.TimeFormat))
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || tag == feed.etag || "W/"+tag == feed.etag {
+				c.Status(http.StatusNotModified)
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := c.GetHeader("If-Modified-Since"); since != "" && !feed.lastModified.IsZero() {
+		if t, err := http.ParseTime(since); err == nil && !feed.lastModified.After(t) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+
+	return false
+}
+
+func feedSelfURL(c *gin.Context) string {
+	return config.APIURL + c.Request.URL.RequestURI()
+}
+
+func writeXML(c *gin.Context, contentType string, v interface{}) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate feed", "detail": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), out...))
+}
+
+// feed RSS 2.0
+func GetRSSFeed(c *gin.Context) {
+	feed, ok := loadFeed(c, "rss")
+	if !ok || feedNotModified(c, feed) {
+		return
+	}
+
+	rss := model.RSS{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		DcNS:      "http://purl.org/dc/elements/1.1/",
+		Channel: model.RSSChannel{
+			Title:         feed.title,
+			Link:          config.SiteURL,
+			Description:   "Latest articles from " + feed.title,
+			Language:      "id",
+			LastBuildDate: feed.lastModified.Format(time.RFC1123Z),
+			AtomLink:      model.RSSAtomLink{Href: feedSelfURL(c), Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+
+	for _, art := range feed.articles {
+		link := articleURL(art.Slug)
+		item := model.RSSItem{
+			Title:       art.Title,
+			Link:        link,
+			Guid:        model.RSSGuid{IsPermaLink: "true", Value: link},
+			Description: art.Excerpt,
+			Content:     art.Description,
+			Creator:     art.User,
+			Category:    art.Category,
+			PubDate:     art.CreatedAt.Format(time.RFC1123Z),
+		}
+		if art.Thumbnail != "" {
+			item.Enclosure = &model.RSSEnclosure{
+				Url:    art.Thumbnail,
+				Length: "0",
+				Type:   mime.TypeByExtension(filepath.Ext(art.Thumbnail)),
+			}
+		}
+		rss.Channel.Items = append(rss.Channel.Items, item)
+	}
+
+	writeXML(c, "application/rss+xml; charset=utf-8", rss)
+}
+
+// feed Atom 1.0
+func GetAtomFeed(c *gin.Context) {
+	feed, ok := loadFeed(c, "atom")
+	if !ok || feedNotModified(c, feed) {
+		return
+	}
+
+	atom := model.AtomFeed{
+		Title:   feed.title,
+		Id:      feedSelfURL(c),
+		Updated: feed.lastModified.Format(time.RFC3339),
+		Links: []model.AtomLink{
+			{Href: feedSelfURL(c), Rel: "self", Type: "application/atom+xml"},
+			{Href: config.SiteURL, Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	for _, art := range feed.articles {
+		link := articleURL(art.Slug)
+		entry := model.AtomEntry{
+			Title:     art.Title,
+			Id:        link,
+			Updated:   art.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: art.CreatedAt.UTC().Format(time.RFC3339),
+			Links:     []model.AtomLink{{Href: link, Rel: "alternate", Type: "text/html"}},
+			Author:    model.AtomAuthor{Name: art.User},
+			Category:  &model.AtomCategory{Term: art.Category},
+			Summary:   model.AtomText{Type: "text", Body: art.Excerpt},
+			Content:   model.AtomText{Type: "html", Body: art.Description},
+		}
+		if art.Thumbnail != "" {
+			entry.Links = append(entry.Links, model.AtomLink{
+				Href: art.Thumbnail,
+				Rel:  "enclosure",
+				Type: mime.TypeByExtension(filepath.Ext(art.Thumbnail)),
+			})
+		}
+		atom.Entries = append(atom.Entries, entry)
+	}
+
+	writeXML(c, "application/atom+xml; charset=utf-8", atom)
+}
+
+// feed JSON Feed 1.1
+func GetJSONFeed(c *gin.Context) {
+	feed, ok := loadFeed(c, "json")
+	if !ok || feedNotModified(c, feed) {
+		return
+	}
+
+	jsonFeed := model.JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.title,
+		HomePageURL: config.SiteURL,
+		FeedURL:     feedSelfURL(c),
+		Description: "Latest articles from " + feed.title,
+		Items:       []model.JSONFeedItem{},
+	}
+
+	for _, art := range feed.articles {
+		link := articleURL(art.Slug)
+		jsonFeed.Items = append(jsonFeed.Items, model.JSONFeedItem{
+			Id:            link,
+			Url:           link,
+			Title:         art.Title,
+			ContentHTML:   art.Description,
+			Summary:       art.Excerpt,
+			Image:         art.Thumbnail,
+			DatePublished: art.CreatedAt.UTC().Format(time.RFC3339),
+			DateModified:  art.UpdatedAt.UTC().Format(time.RFC3339),
+			Authors:       []model.JSONFeedAuthor{{Name: art.User}},
+			Tags:          []string{art.Category},
+		})
+	}
+
+	c.Header("Content-Type", "application/feed+json; charset=utf-8")
+	c.JSON(http.StatusOK, jsonFeed)
+}
@@ -0,0 +1,378 @@
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9 ()\-]{6,30}$`)
+
+// kirim inquiry untuk produk (publik)
+func CreateInquiry(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req model.InquiryRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	phone := strings.TrimSpace(req.Phone)
+	if phone != "" && !phonePattern.MatchString(phone) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []string{"Phone is invalid"}})
+		return
+	}
+
+	// honeypot: field "website" disembunyikan di form, hanya bot yang mengisinya
+	if c.PostForm("website") != "" {
+		c.JSON(http.StatusCreated, gin.H{"message": "Inquiry submitted successfully"})
+		return
+	}
+
+	var productTitle string
+	err = config.DB.QueryRow(`SELECT title FROM products WHERE id = $1`, productID).Scan(&productTitle)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	// disimpan sebagai teks biasa
+	name := strings.TrimSpace(utils.HTMLToPlainText(req.Name))
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []string{"Name is required"}})
+		return
+	}
+
+	_, err = config.DB.Exec(`
+		INSERT INTO inquiries (product_id, product_title, plan, name, email, phone, message, status, ip_hash, user_agent, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, productID, productTitle, strings.TrimSpace(utils.HTMLToPlainText(req.Plan)), name,
+		strings.ToLower(strings.TrimSpace(req.Email)), phone, utils.HTMLToPlainText(req.Message), model.InquiryStatusNew,
+		analytics.VisitorHash(c.ClientIP(), ""), c.Request.UserAgent(), time.Now(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Inquiry submitted successfully"})
+}
+
+const inquirySelect = `
+	SELECT
+		i.id, i.product_id, i.product_title, i.plan, i.name, i.email, i.phone, i.message, i.status,
+		i.assigned_to, COALESCE(u.name, '') AS assignee_name, i.created_at, i.updated_at
+	FROM inquiries i
+	LEFT JOIN users u ON u.id = i.assigned_to`
+
+func scanInquiry(scanner interface{ Scan(...interface{}) error }) (model.Inquiry, error) {
+	var inquiry model.Inquiry
+	var productID, assignedTo sql.NullInt64
+	err := scanner.Scan(
+		&inquiry.Id,
+		&productID,
+		&inquiry.ProductTitle,
+		&inquiry.Plan,
+		&inquiry.Name,
+		&inquiry.Email,
+		&inquiry.Phone,
+		&inquiry.Message,
+		&inquiry.Status,
+		&assignedTo,
+		&inquiry.AssigneeName,
+		&inquiry.CreatedAt,
+		&inquiry.UpdatedAt,
+	)
+	if productID.Valid {
+		id := int(productID.Int64)
+		inquiry.ProductId = &id
+	}
+	if assignedTo.Valid {
+		id := int(assignedTo.Int64)
+		inquiry.AssignedTo = &id
+	}
+	return inquiry, err
+}
+
+// get daftar inquiry (admin), filter ?status= ?assigned_to= ?product_id=
+func GetAllInquiries(c *gin.Context) {
+	query := inquirySelect + ` WHERE 1 = 1`
+	var args []interface{}
+
+	if status := c.Query("status"); status != "" {
+		switch status {
+		case model.InquiryStatusNew, model.InquiryStatusContacted, model.InquiryStatusWon, model.InquiryStatusLost:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		args = append(args, status)
+		query += fmt.Sprintf(" AND i.status = $%d", len(args))
+	}
+
+	// assigned_to=none untuk inquiry yang belum ditangani siapa pun
+	if assigned := c.Query("assigned_to"); assigned == "none" {
+		query += " AND i.assigned_to IS NULL"
+	} else if assigned == "me" {
+		args = append(args, c.GetInt("user_id"))
+		query += fmt.Sprintf(" AND i.assigned_to = $%d", len(args))
+	} else if assigned != "" {
+		userID, err := strconv.Atoi(assigned)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assigned_to"})
+			return
+		}
+		args = append(args, userID)
+		query += fmt.Sprintf(" AND i.assigned_to = $%d", len(args))
+	}
+
+	if productParam := c.Query("product_id"); productParam != "" {
+		productID, err := strconv.Atoi(productParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product_id"})
+			return
+		}
+		args = append(args, productID)
+		query += fmt.Sprintf(" AND i.product_id = $%d", len(args))
+	}
+
+	query += " ORDER BY i.created_at DESC"
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	inquiries := []model.Inquiry{}
+	for rows.Next() {
+		inquiry, err := scanInquiry(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		inquiries = append(inquiries, inquiry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": inquiries})
+}
+
+// get detail inquiry beserta catatan (admin)
+func GetInquiry(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	inquiry, err := scanInquiry(config.DB.QueryRow(inquirySelect+` WHERE i.id = $1`, id))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT n.id, n.user_id, COALESCE(u.name, ''), n.note, n.created_at
+		FROM inquiry_notes n
+		LEFT JOIN users u ON u.id = n.user_id
+		WHERE n.inquiry_id = $1
+		ORDER BY n.created_at ASC
+	`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	inquiry.Notes = []model.InquiryNote{}
+	for rows.Next() {
+		var note model.InquiryNote
+		var userID sql.NullInt64
+		if err := rows.Scan(&note.Id, &userID, &note.UserName, &note.Note, &note.CreatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if userID.Valid {
+			uid := int(userID.Int64)
+			note.UserId = &uid
+		}
+		inquiry.Notes = append(inquiry.Notes, note)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": inquiry})
+}
+
+// ubah status inquiry (new / contacted / won / lost)
+func UpdateInquiryStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req model.InquiryStatusRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	result, err := config.DB.Exec(`
+		UPDATE inquiries SET status = $1, updated_at = $2 WHERE id = $3
+	`, req.Status, time.Now(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
+// assign inquiry ke user admin (user_id kosong = lepas)
+func AssignInquiry(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req model.InquiryAssignRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	var assignee interface{}
+	if req.UserId != 0 {
+		var exists int
+		err := config.DB.QueryRow(`SELECT 1 FROM users WHERE id = $1`, req.UserId).Scan(&exists)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return
+		}
+		assignee = req.UserId
+	}
+
+	result, err := config.DB.Exec(`
+		UPDATE inquiries SET assigned_to = $1, updated_at = $2 WHERE id = $3
+	`, assignee, time.Now(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
+// tambah catatan tindak lanjut oleh user yang sedang login
+func CreateInquiryNote(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req model.InquiryNoteRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	var exists int
+	err = config.DB.QueryRow(`SELECT 1 FROM inquiries WHERE id = $1`, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	_, err = config.DB.Exec(`
+		INSERT INTO inquiry_notes (inquiry_id, user_id, note, created_at) VALUES ($1, $2, $3, $4)
+	`, id, c.GetInt("user_id"), strings.TrimSpace(req.Note), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	_, _ = config.DB.Exec(`UPDATE inquiries SET updated_at = $1 WHERE id = $2`, time.Now(), id)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Data created successfully"})
+}
@@ -0,0 +1,393 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/mailer"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// superadmin hanya bisa diberikan oleh superadmin, role lain oleh admin / superadmin
+func canAssignRole(actorRole, role string) bool {
+	if role == model.RoleSuperadmin {
+		return actorRole == model.RoleSuperadmin
+	}
+	return isUserManager(actorRole)
+}
+
+const invitationSelect = `
+	SELECT i.id, i.email, i.role, i.status, i.invited_by, COALESCE(u.name, ''),
+		i.expires_at, i.accepted_at, i.accepted_user_id, i.revoked_at, i.created_at
+	FROM (
+		SELECT *,
+			CASE
+				WHEN accepted_at IS NOT NULL THEN 'accepted'
+				WHEN revoked_at IS NOT NULL THEN 'revoked'
+				WHEN expires_at <= NOW() THEN 'expired'
+				ELSE 'pending'
+			END AS status
+		FROM user_invitations
+	) i
+	LEFT JOIN users u ON u.id = i.invited_by`
+
+func scanInvitation(scanner interface{ Scan(...interface{}) error }) (model.Invitation, error) {
+	var inv model.Invitation
+	var invitedBy, acceptedUserID sql.NullInt64
+	var acceptedAt, revokedAt sql.NullTime
+	err := scanner.Scan(
+		&inv.Id, &inv.Email, &inv.Role, &inv.Status, &invitedBy, &inv.InvitedByName,
+		&inv.ExpiresAt, &acceptedAt, &acceptedUserID, &revokedAt, &inv.CreatedAt,
+	)
+	if invitedBy.Valid {
+		id := int(invitedBy.Int64)
+		inv.InvitedBy = &id
+	}
+	if acceptedUserID.Valid {
+		id := int(acceptedUserID.Int64)
+		inv.AcceptedUserId = &id
+	}
+	if acceptedAt.Valid {
+		inv.AcceptedAt = &acceptedAt.Time
+	}
+	if revokedAt.Valid {
+		inv.RevokedAt = &revokedAt.Time
+	}
+	return inv, err
+}
+
+// daftar undangan (admin), opsional ?status=pending|accepted|expired|revoked
+func GetAllInvitations(c *gin.Context) {
+	query := invitationSelect
+	var args []interface{}
+	if status := c.Query("status"); status != "" {
+		switch status {
+		case model.InvitationStatusPending, model.InvitationStatusAccepted, model.InvitationStatusExpired, model.InvitationStatusRevoked:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		query += ` WHERE i.status = $1`
+		args = append(args, status)
+	}
+	query += ` ORDER BY i.created_at DESC`
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	invitations := []model.Invitation{}
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		invitations = append(invitations, inv)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": invitations})
+}
+
+// buat undangan + kirim link sekali pakai ke email (admin), undangan aktif
+// sebelumnya untuk email yang sama dibatalkan
+func CreateInvitation(c *gin.Context) {
+	var req model.InvitationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	if !canAssignRole(c.GetString("role"), role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to assign this role"})
+		return
+	}
+
+	var existingID int
+	err := config.DB.QueryRow(`SELECT id FROM users WHERE LOWER(email) = $1`, email).Scan(&existingID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+		return
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	ttl := config.InvitationTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE user_invitations SET revoked_at = $1, updated_at = $1
+		WHERE LOWER(email) = $2 AND accepted_at IS NULL AND revoked_at IS NULL
+	`, time.Now(), email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	secret := utils.RandomToken(32)
+	var invitationID int
+	err = tx.QueryRow(`
+		INSERT INTO user_invitations (email, role, token_hash, invited_by, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id
+	`, email, role, utils.HashToken(secret), c.GetInt("user_id"), expiresAt, time.Now()).Scan(&invitationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	link := config.InvitationURL + "?token=" + url.QueryEscape(signIDToken(invitationID, secret))
+
+	var inviterName string
+	_ = config.DB.QueryRow(`SELECT name FROM users WHERE id = $1`, c.GetInt("user_id")).Scan(&inviterName)
+
+	// gagal kirim email tidak membatalkan undangan, link tetap bisa dibagikan manual oleh admin
+	emailQueued := true
+	_, err = mailer.Enqueue("user_invitation", email, "", mailer.Data{
+		"InviterName": inviterName,
+		"Role":        role,
+		"AcceptURL":   link,
+		"ExpiresAt":   expiresAt.Format(time.RFC1123Z),
+	})
+	if err != nil {
+		emailQueued = false
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Invitation created successfully",
+		"id":           invitationID,
+		"link":         link,
+		"expires_at":   expiresAt,
+		"email_queued": emailQueued,
+	})
+}
+
+// batalkan undangan yang belum diterima (admin)
+func RevokeInvitation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	result, err := config.DB.Exec(`
+		UPDATE user_invitations SET revoked_at = $1, updated_at = $1
+		WHERE id = $2 AND accepted_at IS NULL AND revoked_at IS NULL
+	`, time.Now(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked successfully"})
+}
+
+// ambil undangan yang masih berlaku dari token, return nil jika tidak valid
+func pendingInvitation(q interface {
+	QueryRow(string, ...interface{}) *sql.Row
+}, token string, forUpdate bool) (*model.Invitation, error) {
+	id, secret, ok := parseIDToken(token)
+	if !ok {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, email, role, token_hash, expires_at
+		FROM user_invitations
+		WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > $2`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	var inv model.Invitation
+	var tokenHash string
+	err := q.QueryRow(query, id, time.Now()).Scan(&inv.Id, &inv.Email, &inv.Role, &tokenHash, &inv.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(tokenHash), []byte(utils.HashToken(secret))) != 1 {
+		return nil, nil
+	}
+	inv.Status = model.InvitationStatusPending
+	return &inv, nil
+}
+
+// cek token undangan untuk form frontend (publik), ?token=
+func VerifyInvitation(c *gin.Context) {
+	inv, err := pendingInvitation(config.DB, c.Query("token"), false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if inv == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid or expired invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"email":      inv.Email,
+		"role":       inv.Role,
+		"expires_at": inv.ExpiresAt,
+	}})
+}
+
+// terima undangan (publik): buat akun dengan email + role dari undangan,
+// link langsung tidak berlaku lagi
+func AcceptInvitation(c *gin.Context) {
+	var req model.AcceptInvitationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	file, err := c.FormFile("profile")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile image is required"})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE agar link yang sama tidak bisa dipakai dua kali bersamaan
+	inv, err := pendingInvitation(tx, req.Token, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if inv == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired invitation"})
+		return
+	}
+
+	var existingID int
+	err = tx.QueryRow(`SELECT id FROM users WHERE LOWER(email) = LOWER($1)`, inv.Email).Scan(&existingID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+		return
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	// Upload profile
+	os.MkdirAll("uploads", os.ModePerm)
+	filename := uuid.New().String() + filepath.Ext(file.Filename)
+	savePath := "uploads/" + filename
+	if err := c.SaveUploadedFile(file, savePath); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		return
+	}
+
+	var userID, tokenVersion int
+	err = tx.QueryRow(`
+		INSERT INTO users (name, email, password, profile, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id, token_version
+	`, strings.TrimSpace(req.Name), inv.Email, hashedPassword, "/uploads/"+filename, inv.Role, time.Now()).Scan(&userID, &tokenVersion)
+	if err != nil {
+		os.Remove(savePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	_, err = tx.Exec(`
+		UPDATE user_invitations SET accepted_at = $1, accepted_user_id = $2, updated_at = $1 WHERE id = $3
+	`, time.Now(), userID, inv.Id)
+	if err != nil {
+		os.Remove(savePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		os.Remove(savePath)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	// langsung login setelah akun dibuat
+	token, err := generateToken(userID, tokenVersion)
+	if err != nil {
+		c.JSON(http.StatusCreated, gin.H{"message": "Account created successfully"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Account created successfully",
+		"token":   token,
+	})
+}
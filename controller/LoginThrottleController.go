@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"database/sql"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gin-gonic/gin"
+)
+
+// lama tahanan setelah gagal ke-n: 0 selama masih dalam jatah gratis, lalu berlipat dua
+func loginBackoff(failures, freeAttempts int) time.Duration {
+	if failures <= freeAttempts {
+		return 0
+	}
+	d := config.LoginBackoffBase
+	for i := freeAttempts + 1; i < failures && d < config.LoginMaxLockout; i++ {
+		d *= 2
+	}
+	if d > config.LoginMaxLockout {
+		d = config.LoginMaxLockout
+	}
+	return d
+}
+
+// sisa waktu kunci terlama untuk akun / IP ini, 0 jika boleh mencoba login
+func loginLockedFor(email, ipHash string, now time.Time) (time.Duration, error) {
+	var lockedUntil sql.NullTime
+	err := config.DB.QueryRow(`
+		SELECT MAX(locked_until) FROM login_throttles
+		WHERE ((scope = 'account' AND key = $1) OR (scope = 'ip' AND key = $2)) AND locked_until > $3
+	`, email, ipHash, now).Scan(&lockedUntil)
+	if err != nil || !lockedUntil.Valid {
+		return 0, err
+	}
+	return lockedUntil.Time.Sub(now), nil
+}
+
+// naikkan counter gagal akun + IP dan hitung kunci berikutnya
+func recordLoginFailure(email, ipHash string, now time.Time) error {
+	for _, t := range []struct {
+		scope, key   string
+		freeAttempts int
+	}{
+		{model.ThrottleScopeAccount, email, config.LoginAccountFreeAttempts},
+		{model.ThrottleScopeIP, ipHash, config.LoginIPFreeAttempts},
+	} {
+		var failures int
+		err := config.DB.QueryRow(`
+			INSERT INTO login_throttles (scope, key, failures, last_failure_at)
+			VALUES ($1, $2, 1, $3)
+			ON CONFLICT (scope, key) DO UPDATE SET
+				failures = CASE WHEN login_throttles.last_failure_at < $4 THEN 1 ELSE login_throttles.failures + 1 END,
+				last_failure_at = EXCLUDED.last_failure_at
+			RETURNING failures
+		`, t.scope, t.key, now, now.Add(-config.LoginFailureWindow)).Scan(&failures)
+		if err != nil {
+			return err
+		}
+
+		if wait := loginBackoff(failures, t.freeAttempts); wait > 0 {
+			_, err = config.DB.Exec(`
+				UPDATE login_throttles SET locked_until = $1 WHERE scope = $2 AND key = $3
+			`, now.Add(wait), t.scope, t.key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// login berhasil: counter akun di-reset, counter lama yang sudah tidak relevan dibersihkan
+func clearLoginFailures(email string, now time.Time) {
+	_, err := config.DB.Exec(`DELETE FROM login_throttles WHERE scope = 'account' AND key = $1`, email)
+	if err == nil {
+		_, err = config.DB.Exec(`
+			DELETE FROM login_throttles
+			WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)
+		`, now.Add(-config.LoginFailureWindow), now)
+	}
+	if err != nil {
+		log.Printf("login throttle: %v", err)
+	}
+}
+
+func recordLoginAttempt(c *gin.Context, email string, userID int, ipHash string, success bool, reason string) {
+	var user interface{}
+	if userID > 0 {
+		user = userID
+	}
+	_, err := config.DB.Exec(`
+		INSERT INTO login_attempts (email, user_id, ip_hash, user_agent, success, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, email, user, ipHash, c.Request.UserAgent(), success, reason, time.Now())
+	if err != nil {
+		log.Printf("login attempt audit: %v", err)
+	}
+}
+
+func tooManyLoginAttempts(c *gin.Context, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Too many failed login attempts, try again later",
+		"retry_after": seconds,
+	})
+}
+
+// audit percobaan login (admin), filter ?email, ?ip_hash, ?success=true|false, paging ?before=<id>
+func GetLoginAttempts(c *gin.Context) {
+	limit, ok := parseLimit(c, 50)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	query := `
+		SELECT id, email, user_id, ip_hash, user_agent, success, reason, created_at
+		FROM login_attempts
+		WHERE TRUE`
+	var args []interface{}
+	if email := c.Query("email"); email != "" {
+		args = append(args, normalizeEmail(email))
+		query += ` AND email = $` + strconv.Itoa(len(args))
+	}
+	if ipHash := c.Query("ip_hash"); ipHash != "" {
+		args = append(args, ipHash)
+		query += ` AND ip_hash = $` + strconv.Itoa(len(args))
+	}
+	if success := c.Query("success"); success != "" {
+		value, err := strconv.ParseBool(success)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid success"})
+			return
+		}
+		args = append(args, value)
+		query += ` AND success = $` + strconv.Itoa(len(args))
+	}
+	if before := c.Query("before"); before != "" {
+		id, err := strconv.Atoi(before)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid before"})
+			return
+		}
+		args = append(args, id)
+		query += ` AND id < $` + strconv.Itoa(len(args))
+	}
+	args = append(args, limit)
+	query += ` ORDER BY id DESC LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	attempts := []model.LoginAttempt{}
+	for rows.Next() {
+		var attempt model.LoginAttempt
+		var userID sql.NullInt64
+		if err := rows.Scan(&attempt.Id, &attempt.Email, &userID, &attempt.IpHash, &attempt.UserAgent, &attempt.Success, &attempt.Reason, &attempt.CreatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if userID.Valid {
+			id := int(userID.Int64)
+			attempt.UserId = &id
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": attempts})
+}
+
+// akun / IP yang sedang terkunci (admin), ?all=1 untuk semua counter gagal
+func GetLoginLockouts(c *gin.Context) {
+	query := `
+		SELECT scope, key, failures, last_failure_at, locked_until
+		FROM login_throttles`
+	var args []interface{}
+	if c.Query("all") != "1" {
+		query += ` WHERE locked_until > $1`
+		args = append(args, time.Now())
+	}
+	query += ` ORDER BY last_failure_at DESC`
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	lockouts := []model.LoginLockout{}
+	for rows.Next() {
+		var lockout model.LoginLockout
+		var lockedUntil sql.NullTime
+		if err := rows.Scan(&lockout.Scope, &lockout.Key, &lockout.Failures, &lockout.LastFailureAt, &lockedUntil); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if lockedUntil.Valid {
+			lockout.LockedUntil = &lockedUntil.Time
+			lockout.Locked = lockedUntil.Time.After(now)
+		}
+		lockouts = append(lockouts, lockout)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": lockouts})
+}
+
+// buka kunci + reset counter akun (key = email) atau IP (key = ip_hash)
+func UnlockLogin(c *gin.Context) {
+	scope := c.Param("scope")
+	key := c.Param("key")
+	switch scope {
+	case model.ThrottleScopeAccount:
+		key = normalizeEmail(key)
+	case model.ThrottleScopeIP:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scope"})
+		return
+	}
+
+	result, err := config.DB.Exec(`DELETE FROM login_throttles WHERE scope = $1 AND key = $2`, scope, key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Login unlocked successfully"})
+}
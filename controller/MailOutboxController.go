@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gin-gonic/gin"
+)
+
+// daftar email di outbox (admin), opsional ?status=pending|sent|failed
+func GetMailOutbox(c *gin.Context) {
+	limit, ok := parseLimit(c, 50)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	query := `
+		SELECT id, template, to_address, reply_to, subject, status, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at
+		FROM mail_outbox`
+	args := []interface{}{limit}
+	if status := c.Query("status"); status != "" {
+		if status != model.MailStatusPending && status != model.MailStatusSent && status != model.MailStatusFailed {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		query += ` WHERE status = $2`
+		args = append(args, status)
+	}
+	query += ` ORDER BY created_at DESC LIMIT $1`
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	mails := []model.MailOutbox{}
+	for rows.Next() {
+		var mail model.MailOutbox
+		var sentAt sql.NullTime
+		if err := rows.Scan(&mail.Id, &mail.Template, &mail.To, &mail.ReplyTo, &mail.Subject, &mail.Status, &mail.Attempts, &mail.LastError, &mail.NextAttemptAt, &sentAt, &mail.CreatedAt, &mail.UpdatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		if sentAt.Valid {
+			mail.SentAt = &sentAt.Time
+		}
+		mails = append(mails, mail)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": mails})
+}
+
+// kirim ulang email yang gagal / masih menunggu, dijalankan worker di putaran berikutnya
+func RetryMailOutbox(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	result, err := config.DB.Exec(`
+		UPDATE mail_outbox
+		SET status = 'pending', attempts = 0, next_attempt_at = $1, updated_at = $1
+		WHERE id = $2 AND status <> 'sent'
+	`, time.Now(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
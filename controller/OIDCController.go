@@ -0,0 +1,294 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/jwtkeys"
+	"github.com/gibranfajar/backend-codetech/oidc"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errSSONoAccount       = errors.New("no account for this email")
+	errSSOAccountMismatch = errors.New("account is linked to another identity")
+	errSSOLinkRequires2FA = errors.New("account must enable two-factor authentication before linking")
+)
+
+type ssoUser struct {
+	id               int
+	role             string
+	tokenVersion     int
+	twoFactorEnabled bool
+}
+
+// cookie pengikat state ke browser yang memulai login (mencegah login CSRF)
+const ssoStateCookie = "oidc_state"
+
+func setSSOStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoStateCookie, value, maxAge, "/api/oidc", "", strings.HasPrefix(config.OIDCRedirectURL, "https://"), true)
+}
+
+// hasil login dikirim ke frontend lewat fragment (#) agar token tidak
+// tercatat di log server maupun header Referer
+func ssoRedirect(c *gin.Context, values url.Values) {
+	c.Redirect(http.StatusFound, config.OIDCFrontendURL+"#"+values.Encode())
+}
+
+func ssoError(c *gin.Context, code string) {
+	ssoRedirect(c, url.Values{"error": {code}})
+}
+
+// provisioning otomatis opt-in, dibatasi domain, dan tidak pernah memberi role pengelola user
+func ssoProvisionAllowed(email string) bool {
+	if config.OIDCDefaultRole == "" || len(config.OIDCAllowedDomains) == 0 || isUserManager(config.OIDCDefaultRole) {
+		return false
+	}
+	domain := email[strings.LastIndex(email, "@")+1:]
+	for _, allowed := range config.OIDCAllowedDomains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// cari user dari claim sub (sudah pernah login SSO), lalu dari email terverifikasi;
+// login SSO pertama menautkan sub ke user tersebut, user baru dibuat dengan
+// config.OIDCDefaultRole jika belum ada
+func findOrProvisionSSOUser(claims *oidc.Claims, email string) (ssoUser, error) {
+	var user ssoUser
+	err := config.DB.QueryRow(`
+		SELECT id, role, token_version, totp_enabled_at IS NOT NULL FROM users WHERE oidc_subject = $1
+	`, claims.Subject).Scan(&user.id, &user.role, &user.tokenVersion, &user.twoFactorEnabled)
+	if err != sql.ErrNoRows {
+		return user, err
+	}
+
+	// sub lain yang sudah tertaut ke email ini tidak boleh mengambil alih akun
+	var subject sql.NullString
+	err = config.DB.QueryRow(`
+		SELECT id, role, token_version, totp_enabled_at IS NOT NULL, oidc_subject FROM users WHERE LOWER(email) = $1
+	`, email).Scan(&user.id, &user.role, &user.tokenVersion, &user.twoFactorEnabled, &subject)
+	if err == nil {
+		if subject.Valid {
+			return user, errSSOAccountMismatch
+		}
+		// akun role wajib 2FA hanya ditautkan jika sudah enroll TOTP, sehingga
+		// email yang diubah ke alamat orang lain tidak cukup untuk mengambil alih akun
+		if config.TwoFactorRequired(user.role) && !user.twoFactorEnabled {
+			return user, errSSOLinkRequires2FA
+		}
+		_, err = config.DB.Exec(`UPDATE users SET oidc_subject = $1, updated_at = $2 WHERE id = $3`, claims.Subject, time.Now(), user.id)
+		return user, err
+	} else if err != sql.ErrNoRows {
+		return user, err
+	}
+
+	if !ssoProvisionAllowed(email) {
+		return user, errSSONoAccount
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = email[:strings.Index(email, "@")]
+	}
+	// password acak yang tidak diketahui siapa pun, user bisa memakai lupa password
+	// jika nanti butuh login tanpa SSO
+	hashedPassword, err := utils.HashPassword(utils.RandomToken(32))
+	if err != nil {
+		return user, err
+	}
+
+	user.role = config.OIDCDefaultRole
+	err = config.DB.QueryRow(`
+		INSERT INTO users (name, email, password, profile, role, oidc_subject, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		RETURNING id, token_version
+	`, name, email, hashedPassword, "", user.role, claims.Subject, time.Now()).Scan(&user.id, &user.tokenVersion)
+	if err == nil {
+		log.Printf("sso: provisioned user %d (%s) with role %s", user.id, email, user.role)
+	}
+	return user, err
+}
+
+// mulai login SSO: simpan state, PKCE verifier dan nonce lalu redirect ke identity provider
+func OIDCLogin(c *gin.Context) {
+	provider := oidc.Default()
+	if provider == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Single sign-on is not configured"})
+		return
+	}
+
+	state := utils.RandomToken(32)
+	verifier := utils.RandomToken(48)
+	nonce := utils.RandomToken(24)
+	now := time.Now()
+
+	// state kadaluarsa dibersihkan sekalian
+	_, _ = config.DB.Exec(`DELETE FROM oidc_states WHERE expires_at < $1`, now)
+
+	_, err := config.DB.Exec(`
+		INSERT INTO oidc_states (state_hash, code_verifier, nonce, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, utils.HashToken(state), verifier, nonce, now.Add(config.OIDCStateTTL), now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	authURL, err := provider.AuthCodeURL(c.Request.Context(), state, nonce, verifier)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Identity provider unavailable", "detail": err.Error()})
+		return
+	}
+
+	// callback hanya diterima dari browser yang sama (cookie SameSite=Lax ikut terkirim
+	// pada redirect top-level dari identity provider)
+	setSSOStateCookie(c, utils.HashToken(state), int(config.OIDCStateTTL.Seconds()))
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// callback dari identity provider: tukar code dengan ID token, cocokkan user,
+// lalu redirect ke frontend dengan JWT (#token=...) atau kode error (#error=...)
+func OIDCCallback(c *gin.Context) {
+	provider := oidc.Default()
+	if provider == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Single sign-on is not configured"})
+		return
+	}
+
+	// user membatalkan / ditolak di identity provider
+	if errCode := c.Query("error"); errCode != "" {
+		setSSOStateCookie(c, "", -1)
+		ssoError(c, errCode)
+		return
+	}
+
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		ssoError(c, "invalid_request")
+		return
+	}
+
+	// state harus milik browser ini, bukan callback login orang lain yang dikirim ke korban
+	stateCookie, _ := c.Cookie(ssoStateCookie)
+	setSSOStateCookie(c, "", -1)
+	if stateCookie == "" || subtle.ConstantTimeCompare([]byte(stateCookie), []byte(utils.HashToken(state))) != 1 {
+		ssoError(c, "invalid_state")
+		return
+	}
+
+	// state sekali pakai
+	var verifier, nonce string
+	var expiresAt time.Time
+	err := config.DB.QueryRow(`
+		DELETE FROM oidc_states WHERE state_hash = $1 RETURNING code_verifier, nonce, expires_at
+	`, utils.HashToken(state)).Scan(&verifier, &nonce, &expiresAt)
+	if err == sql.ErrNoRows || (err == nil && time.Now().After(expiresAt)) {
+		ssoError(c, "invalid_state")
+		return
+	} else if err != nil {
+		log.Printf("sso: %v", err)
+		ssoError(c, "server_error")
+		return
+	}
+
+	ctx := c.Request.Context()
+	rawIDToken, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		log.Printf("sso: %v", err)
+		ssoError(c, "token_exchange_failed")
+		return
+	}
+
+	claims, err := provider.VerifyIDToken(ctx, rawIDToken, nonce)
+	if err != nil {
+		log.Printf("sso: %v", err)
+		ssoError(c, "invalid_id_token")
+		return
+	}
+
+	email := normalizeEmail(claims.Email)
+	ipHash := analytics.VisitorHash(c.ClientIP(), "")
+	if !strings.Contains(email, "@") || !claims.EmailVerified {
+		recordLoginAttempt(c, email, 0, ipHash, false, "sso_email_not_verified")
+		ssoError(c, "email_not_verified")
+		return
+	}
+
+	user, err := findOrProvisionSSOUser(claims, email)
+	switch {
+	case err == errSSONoAccount:
+		recordLoginAttempt(c, email, 0, ipHash, false, "sso_no_account")
+		ssoError(c, "account_not_found")
+		return
+	case err == errSSOLinkRequires2FA:
+		recordLoginAttempt(c, email, user.id, ipHash, false, "sso_link_requires_2fa")
+		ssoError(c, "two_factor_setup_required")
+		return
+	case err == errSSOAccountMismatch:
+		recordLoginAttempt(c, email, user.id, ipHash, false, "sso_account_mismatch")
+		ssoError(c, "account_mismatch")
+		return
+	case err != nil:
+		log.Printf("sso: %v", err)
+		ssoError(c, "server_error")
+		return
+	}
+
+	// user yang sudah enroll TOTP selalu lanjut ke /api/login/2fa seperti login password
+	if user.twoFactorEnabled {
+		challenge, err := twoFactorChallenge(user.id, user.tokenVersion)
+		if err != nil {
+			log.Printf("sso: %v", err)
+			ssoError(c, "server_error")
+			return
+		}
+		ssoRedirect(c, url.Values{
+			"two_factor_required": {"true"},
+			"challenge_token":     {challenge},
+			"expires_in":          {strconv.Itoa(int(config.TwoFactorChallengeTTL.Seconds()))},
+		})
+		return
+	}
+
+	recordLoginAttempt(c, email, user.id, ipHash, true, "sso")
+
+	// amr dipakai middleware untuk kebijakan 2FA wajib, "mfa" hanya jika
+	// MFA identity provider dipercaya (config.OIDCSatisfiesTwoFactor)
+	amr := []string{"oidc"}
+	ssoMFA := config.OIDCSatisfiesTwoFactor && claims.MFA
+	if ssoMFA {
+		amr = append(amr, "mfa")
+	}
+	tokenClaims := loginClaims(user.id, user.tokenVersion)
+	tokenClaims["amr"] = amr
+	tokenString, err := jwtkeys.Sign(tokenClaims)
+	if err != nil {
+		log.Printf("sso: %v", err)
+		ssoError(c, "server_error")
+		return
+	}
+
+	values := url.Values{
+		"token":      {tokenString},
+		"expires_in": {strconv.Itoa(int(config.JWTTTL.Seconds()))},
+	}
+	if config.TwoFactorRequired(user.role) && !ssoMFA {
+		values.Set("two_factor_setup_required", "true")
+	}
+	ssoRedirect(c, values)
+}
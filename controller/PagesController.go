@@ -21,7 +21,7 @@ import (
 func GetAllPages(c *gin.Context) {
 	var pages []model.Pages
 
-	rows, err := config.DB.Query("SELECT id, title, slug, type, description, banner, created_at, updated_at FROM pages")
+	rows, err := config.DB.Query("SELECT id, title, slug, type, description, banner, created_at, updated_at, " + seoSelectColumns + " FROM pages")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
 		return
@@ -34,13 +34,30 @@ func GetAllPages(c *gin.Context) {
 
 	for rows.Next() {
 		var page model.Pages
-		if err := rows.Scan(&page.Id, &page.Title, &page.Slug, &page.Type, &page.Description, &page.Banner, &page.CreatedAt, &page.UpdatedAt); err != nil {
+		var seo model.SeoFields
+		if err := rows.Scan(&page.Id, &page.Title, &page.Slug, &page.Type, &page.Description, &page.Banner, &page.CreatedAt, &page.UpdatedAt,
+			&seo.MetaTitle, &seo.MetaDescription, &seo.CanonicalUrl, &seo.OgImage, &seo.Noindex); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
 			return
 		}
+		page.Seo = &seo
 		pages = append(pages, page)
 	}
 
+	ids := make([]int, len(pages))
+	for i := range pages {
+		ids[i] = pages[i].Id
+	}
+	translations, err := loadTranslations("pages", requestLocale(c), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	for i := range pages {
+		translateField(translations[pages[i].Id], "title", &pages[i].Title)
+		translateField(translations[pages[i].Id], "description", &pages[i].Description)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"data": pages,
 	})
@@ -83,10 +100,18 @@ func CreatePage(c *gin.Context) {
 		return
 	}
 
+	// Metadata SEO (og_image opsional)
+	seo, err := seoFromRequest(c, req.SeoRequest, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload og image"})
+		return
+	}
+
 	// Simpan ke database PostgreSQL
 	query := `
-		INSERT INTO pages (title, slug, type, description, banner, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO pages (title, slug, type, description, banner, created_at, updated_at,
+			meta_title, meta_description, canonical_url, og_image, noindex)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 	_, err = config.DB.Exec(
 		query,
@@ -97,6 +122,11 @@ func CreatePage(c *gin.Context) {
 		"/uploads/"+filename,
 		time.Now(),
 		time.Now(),
+		seo.MetaTitle,
+		seo.MetaDescription,
+		seo.CanonicalUrl,
+		seo.OgImage,
+		seo.Noindex,
 	)
 
 	if err != nil {
@@ -142,9 +172,9 @@ func UpdatePage(c *gin.Context) {
 	description := c.PostForm("description")
 	types := c.PostForm("type")
 
-	// Ambil banner lama dari database
-	var oldBanner string
-	err = config.DB.QueryRow("SELECT banner FROM pages WHERE id = $1", id).Scan(&oldBanner)
+	// Ambil banner & og_image lama dari database
+	var oldBanner, oldOgImage string
+	err = config.DB.QueryRow("SELECT banner, og_image FROM pages WHERE id = $1", id).Scan(&oldBanner, &oldOgImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Page not found"})
 		return
@@ -182,6 +212,13 @@ func UpdatePage(c *gin.Context) {
 		bannerPath = "/uploads/" + filename
 	}
 
+	// Metadata SEO
+	seo, err := seoFromRequest(c, req.SeoRequest, oldOgImage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload og image"})
+		return
+	}
+
 	// Update data di PostgreSQL
 	query := `
 		UPDATE pages
@@ -190,8 +227,13 @@ func UpdatePage(c *gin.Context) {
 			type = $3,
 			description = $4,
 			banner = $5,
-			updated_at = $6
-		WHERE id = $7
+			updated_at = $6,
+			meta_title = $7,
+			meta_description = $8,
+			canonical_url = $9,
+			og_image = $10,
+			noindex = $11
+		WHERE id = $12
 	`
 
 	_, err = config.DB.Exec(
@@ -202,6 +244,11 @@ func UpdatePage(c *gin.Context) {
 		description,
 		bannerPath,
 		time.Now(),
+		seo.MetaTitle,
+		seo.MetaDescription,
+		seo.CanonicalUrl,
+		seo.OgImage,
+		seo.Noindex,
 		id,
 	)
 
@@ -226,8 +273,9 @@ func DeletePage(c *gin.Context) {
 	}
 
 	var page model.Pages
-	query := "SELECT id, banner FROM pages WHERE id = $1"
-	err = config.DB.QueryRow(query, id).Scan(&page.Id, &page.Banner)
+	var ogImage string
+	query := "SELECT id, banner, og_image FROM pages WHERE id = $1"
+	err = config.DB.QueryRow(query, id).Scan(&page.Id, &page.Banner, &ogImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Page not found"})
 		return
@@ -267,6 +315,9 @@ func DeletePage(c *gin.Context) {
 		}
 	}
 
+	removeUploadedFile(ogImage)
+	deleteTranslations("pages", id)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Page deleted successfully",
 	})
@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/mailer"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// kabari pemilik akun bahwa password-nya baru saja diganti
+func notifyPasswordChanged(email, name string) {
+	_, err := mailer.Enqueue("password_changed", email, "", mailer.Data{
+		"Name":      name,
+		"ChangedAt": time.Now().Format(time.RFC1123Z),
+	})
+	if err != nil {
+		log.Printf("password changed notification for %s: %v", email, err)
+	}
+}
+
+// minta link reset password (publik), response selalu sama agar email
+// terdaftar / tidak terdaftar tidak bisa ditebak
+func ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// link dibuat & dikirim di background agar waktu respons / status untuk email
+	// terdaftar dan tidak terdaftar sama persis, kegagalan hanya dicatat di log
+	email, ipHash := strings.TrimSpace(req.Email), analytics.VisitorHash(c.ClientIP(), "")
+	go func() {
+		if err := sendPasswordReset(email, ipHash); err != nil {
+			log.Printf("password reset for %s: %v", email, err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the email is registered, a password reset link has been sent"})
+}
+
+// buat link reset untuk user dengan email tersebut lalu antrekan emailnya,
+// email yang tidak terdaftar diabaikan
+func sendPasswordReset(email, ipHash string) error {
+	var userID int
+	var name string
+	err := config.DB.QueryRow(`
+		SELECT id, email, name FROM users WHERE LOWER(email) = LOWER($1)
+	`, email).Scan(&userID, &email, &name)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// hanya link terbaru yang berlaku
+	if _, err := config.DB.Exec(`DELETE FROM password_resets WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return err
+	}
+
+	secret := utils.RandomToken(32)
+	var resetID int
+	err = config.DB.QueryRow(`
+		INSERT INTO password_resets (user_id, token_hash, expires_at, ip_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, userID, utils.HashToken(secret), time.Now().Add(config.PasswordResetTTL), ipHash, time.Now()).Scan(&resetID)
+	if err != nil {
+		return err
+	}
+
+	token := signIDToken(resetID, secret)
+	_, err = mailer.Enqueue("password_reset", email, "", mailer.Data{
+		"Name":      name,
+		"ResetURL":  config.PasswordResetURL + "?token=" + url.QueryEscape(token),
+		"ExpiresIn": int(config.PasswordResetTTL.Minutes()),
+	})
+	return err
+}
+
+// token link email (reset password, undangan) = <id baris>.<rahasia acak>.<tanda tangan>,
+// database hanya menyimpan hash rahasianya
+func signIDToken(id int, secret string) string {
+	return utils.SignToken(strconv.Itoa(id)+"."+secret, config.AppKey)
+}
+
+// pecah token menjadi id + rahasia setelah tanda tangannya valid
+func parseIDToken(token string) (int, string, bool) {
+	payload, ok := utils.VerifyToken(strings.TrimSpace(token), config.AppKey)
+	if !ok {
+		return 0, "", false
+	}
+
+	idPart, secret, found := strings.Cut(payload, ".")
+	id, err := strconv.Atoi(idPart)
+	if !found || err != nil || secret == "" {
+		return 0, "", false
+	}
+	return id, secret, true
+}
+
+// set password baru dengan token dari email (publik), token hanya bisa dipakai sekali
+func ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	invalid := gin.H{"error": "Invalid or expired token"}
+
+	resetID, secret, ok := parseIDToken(req.Token)
+	if !ok {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE agar dua request dengan token yang sama tidak lolos bersamaan
+	var userID int
+	var tokenHash string
+	err = tx.QueryRow(`
+		SELECT user_id, token_hash FROM password_resets
+		WHERE id = $1 AND used_at IS NULL AND expires_at > $2
+		FOR UPDATE
+	`, resetID, time.Now()).Scan(&userID, &tokenHash)
+	if err == sql.ErrNoRows || (err == nil && subtle.ConstantTimeCompare([]byte(tokenHash), []byte(utils.HashToken(secret))) != 1) {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	// ganti password + cabut semua sesi
+	var email, name string
+	err = tx.QueryRow(`
+		UPDATE users SET password = $1, token_version = token_version + 1, updated_at = $2
+		WHERE id = $3
+		RETURNING email, name
+	`, hashedPassword, time.Now(), userID).Scan(&email, &name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	// token ini dan link lain yang belum dipakai tidak berlaku lagi
+	_, err = tx.Exec(`UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL`, time.Now(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	notifyPasswordChanged(email, name)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// ganti password user yang sedang login, semua sesi lain dicabut dan
+// token baru dikembalikan untuk sesi ini
+func ChangePassword(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	var currentHash, email, name string
+	err := config.DB.QueryRow(`SELECT password, email, name FROM users WHERE id = $1`, userID).Scan(&currentHash, &email, &name)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	if !utils.CheckPasswordHash(req.CurrentPassword, currentHash) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	var tokenVersion int
+	err = config.DB.QueryRow(`
+		UPDATE users SET password = $1, token_version = token_version + 1, updated_at = $2
+		WHERE id = $3
+		RETURNING token_version
+	`, hashedPassword, time.Now(), userID).Scan(&tokenVersion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	// link reset yang masih tertunda ikut dibatalkan
+	_, _ = config.DB.Exec(`UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL`, time.Now(), userID)
+
+	token, err := generateToken(userID, tokenVersion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	notifyPasswordChanged(email, name)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password updated successfully",
+		"token":   token,
+	})
+}
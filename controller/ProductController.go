@@ -7,52 +7,204 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
-// get all data
-func GetAllProduct(c *gin.Context) {
-	var products []model.Product
+type productPricing struct {
+	currency     string
+	price        utils.Money
+	discountType string
+	discount     utils.Money
+	startsAt     *time.Time
+	endsAt       *time.Time
+}
 
-	rows, err := config.DB.Query(`
-		SELECT id, title, description, price, discount, type, icon, created_at, updated_at
-		FROM products
-		ORDER BY created_at DESC
-	`)
+// tanggal diskon: RFC3339 atau YYYY-MM-DD (tanggal akhir berlaku sampai akhir hari)
+func parseDiscountDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, value, time.Local)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
-		return
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return &t, nil
+}
+
+// parse dan validasi harga, diskon tidak boleh melebihi harga
+func productPricingFromRequest(req model.ProductRequest) (productPricing, string) {
+	pricing := productPricing{
+		currency:     strings.ToUpper(req.Currency),
+		discountType: req.DiscountType,
+	}
+	if pricing.currency == "" {
+		pricing.currency = config.DefaultCurrency
+	}
+	if pricing.discountType == "" {
+		pricing.discountType = model.DiscountTypeFixed
+	}
+
+	var err error
+	pricing.price, err = utils.ParseMoney(req.Price)
+	if err != nil || pricing.price < 0 {
+		return pricing, "Invalid price format"
+	}
+
+	if req.Discount != "" {
+		pricing.discount, err = utils.ParseMoney(req.Discount)
+		if err != nil || pricing.discount < 0 {
+			return pricing, "Invalid discount format"
+		}
+	}
+
+	switch pricing.discountType {
+	case model.DiscountTypePercent:
+		if pricing.discount > 100*100 {
+			return pricing, "Percent discount cannot exceed 100"
+		}
+	case model.DiscountTypeFixed:
+		if pricing.discount > pricing.price {
+			return pricing, "Discount cannot exceed price"
+		}
+	}
+
+	if pricing.startsAt, err = parseDiscountDate(req.DiscountStartsAt, false); err != nil {
+		return pricing, "Invalid discount_starts_at, expected RFC3339 or YYYY-MM-DD"
+	}
+	if pricing.endsAt, err = parseDiscountDate(req.DiscountEndsAt, true); err != nil {
+		return pricing, "Invalid discount_ends_at, expected RFC3339 or YYYY-MM-DD"
+	}
+	if pricing.startsAt != nil && pricing.endsAt != nil && !pricing.endsAt.After(*pricing.startsAt) {
+		return pricing, "discount_ends_at must be after discount_starts_at"
+	}
+
+	return pricing, ""
+}
+
+// hitung diskon yang sedang berlaku dan harga akhir
+func computeProductPrice(product *model.Product, now time.Time) {
+	product.DiscountActive = product.Discount > 0 &&
+		(product.DiscountStartsAt == nil || !now.Before(*product.DiscountStartsAt)) &&
+		(product.DiscountEndsAt == nil || now.Before(*product.DiscountEndsAt))
+
+	product.DiscountAmount = 0
+	if product.DiscountActive {
+		if product.DiscountType == model.DiscountTypePercent {
+			product.DiscountAmount = product.Price.Percent(product.Discount)
+		} else {
+			product.DiscountAmount = product.Discount
+		}
+	}
+	if product.DiscountAmount > product.Price {
+		product.DiscountAmount = product.Price
+	}
+
+	product.FinalPrice = product.Price - product.DiscountAmount
+}
+
+// ambil produk (opsional per type) beserta fitur, harga akhir dan terjemahan
+func loadProducts(locale, productType string) ([]model.Product, error) {
+	query := `
+		SELECT id, title, description, currency, price, discount_type, discount, discount_starts_at, discount_ends_at,
+			type, icon, highlighted, created_at, updated_at
+		FROM products`
+	var args []interface{}
+	if productType != "" {
+		query += ` WHERE type = $1 ORDER BY price ASC, id ASC`
+		args = append(args, productType)
+	} else {
+		query += ` ORDER BY created_at DESC`
+	}
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
 	}
 	defer rows.Close()
 
+	products := []model.Product{}
 	for rows.Next() {
 		var product model.Product
+		var startsAt, endsAt sql.NullTime
 		if err := rows.Scan(
 			&product.Id,
 			&product.Title,
 			&product.Description,
+			&product.Currency,
 			&product.Price,
+			&product.DiscountType,
 			&product.Discount,
+			&startsAt,
+			&endsAt,
 			&product.Type,
 			&product.Icon,
+			&product.Highlighted,
 			&product.CreatedAt,
 			&product.UpdatedAt,
 		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
-			return
+			return nil, err
+		}
+		if startsAt.Valid {
+			product.DiscountStartsAt = &startsAt.Time
 		}
+		if endsAt.Valid {
+			product.DiscountEndsAt = &endsAt.Time
+		}
+		computeProductPrice(&product, time.Now())
+		product.Features = []model.ProductFeature{}
 		products = append(products, product)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(products))
+	index := make(map[int]int, len(products))
+	for i := range products {
+		ids[i] = products[i].Id
+		index[products[i].Id] = i
+	}
 
-	// Cek apakah ada data
-	if len(products) == 0 {
-		c.JSON(http.StatusOK, gin.H{"data": []interface{}{}})
+	features, err := loadProductFeatures(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, feature := range features {
+		i := index[feature.ProductId]
+		products[i].Features = append(products[i].Features, feature)
+	}
+
+	translations, err := loadTranslations("products", locale, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		translateField(translations[products[i].Id], "title", &products[i].Title)
+		translateField(translations[products[i].Id], "description", &products[i].Description)
+	}
+
+	return products, nil
+}
+
+// get all data
+func GetAllProduct(c *gin.Context) {
+	products, err := loadProducts(requestLocale(c), c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
 		return
 	}
 
@@ -61,15 +213,16 @@ func GetAllProduct(c *gin.Context) {
 	})
 }
 
+// hanya satu produk highlighted per type
+func unhighlightOtherProducts(id int, productType string) error {
+	_, err := config.DB.Exec(`
+		UPDATE products SET highlighted = FALSE WHERE type = $1 AND id <> $2 AND highlighted = TRUE
+	`, productType, id)
+	return err
+}
+
 // create data
 func CreateProduct(c *gin.Context) {
-	// Ambil form input
-	title := c.PostForm("title")
-	description := c.PostForm("description")
-	priceStr := c.PostForm("price")
-	discountStr := c.PostForm("discount")
-	typeProduct := c.PostForm("type")
-
 	var req model.ProductRequest
 	if err := c.ShouldBind(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -86,23 +239,12 @@ func CreateProduct(c *gin.Context) {
 		return
 	}
 
-	// Konversi price
-	price, err := strconv.ParseFloat(priceStr, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price format"})
+	pricing, errMsg := productPricingFromRequest(req)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
 		return
 	}
 
-	// Konversi discount (jika diisi)
-	var discount float64
-	if discountStr != "" {
-		discount, err = strconv.ParseFloat(discountStr, 64)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid discount format"})
-			return
-		}
-	}
-
 	// Proses file upload (icon opsional)
 	var icon string
 	file, err := c.FormFile("icon")
@@ -125,10 +267,14 @@ func CreateProduct(c *gin.Context) {
 
 	// Simpan ke database PostgreSQL
 	query := `
-		INSERT INTO products (title, description, price, discount, type, icon, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO products (title, description, currency, price, discount_type, discount, discount_starts_at, discount_ends_at,
+			type, icon, highlighted, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
 	`
-	_, err = config.DB.Exec(query, title, description, price, discount, typeProduct, icon, time.Now(), time.Now())
+	var id int
+	err = config.DB.QueryRow(query, req.Title, req.Description, pricing.currency, pricing.price, pricing.discountType, pricing.discount,
+		pricing.startsAt, pricing.endsAt, req.Type, icon, req.Highlighted, time.Now(), time.Now()).Scan(&id)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -138,9 +284,17 @@ func CreateProduct(c *gin.Context) {
 		return
 	}
 
+	if req.Highlighted {
+		if err := unhighlightOtherProducts(id, req.Type); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+			return
+		}
+	}
+
 	// Response sukses
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Product created successfully",
+		"id":      id,
 	})
 }
 
@@ -170,29 +324,12 @@ func UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	// Ambil form data
-	title := c.PostForm("title")
-	description := c.PostForm("description")
-	priceStr := c.PostForm("price")
-	discountStr := c.PostForm("discount")
-	typeProduct := c.PostForm("type")
-
-	// Validasi & parsing angka
-	price, err := strconv.ParseFloat(priceStr, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price format"})
+	pricing, errMsg := productPricingFromRequest(req)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
 		return
 	}
 
-	var discount float64
-	if discountStr != "" {
-		discount, err = strconv.ParseFloat(discountStr, 64)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid discount format"})
-			return
-		}
-	}
-
 	// Ambil data lama (icon lama)
 	var oldIcon string
 	err = config.DB.QueryRow("SELECT icon FROM products WHERE id = $1", id).Scan(&oldIcon)
@@ -239,16 +376,25 @@ func UpdateProduct(c *gin.Context) {
 	// Update data PostgreSQL
 	query := `
 		UPDATE products
-		SET title = $1, description = $2, price = $3, discount = $4, type = $5, icon = $6, updated_at = $7
-		WHERE id = $8
+		SET title = $1, description = $2, currency = $3, price = $4, discount_type = $5, discount = $6,
+			discount_starts_at = $7, discount_ends_at = $8, type = $9, icon = $10, highlighted = $11, updated_at = $12
+		WHERE id = $13
 	`
-	_, err = config.DB.Exec(query, title, description, price, discount, typeProduct, iconPath, time.Now(), id)
+	_, err = config.DB.Exec(query, req.Title, req.Description, pricing.currency, pricing.price, pricing.discountType, pricing.discount,
+		pricing.startsAt, pricing.endsAt, req.Type, iconPath, req.Highlighted, time.Now(), id)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
 		return
 	}
 
+	if req.Highlighted {
+		if err := unhighlightOtherProducts(id, req.Type); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Product updated successfully"})
 }
 
@@ -292,6 +438,8 @@ func DeleteProduct(c *gin.Context) {
 		return
 	}
 
+	deleteTranslations("products", id)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
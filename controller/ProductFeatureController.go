@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+// ambil fitur beberapa produk sekaligus, urut posisi
+func loadProductFeatures(productIDs []int) ([]model.ProductFeature, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT id, product_id, label, value, included, position, created_at, updated_at
+		FROM product_features
+		WHERE product_id = ANY($1)
+		ORDER BY product_id ASC, position ASC, id ASC
+	`, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var features []model.ProductFeature
+	for rows.Next() {
+		var feature model.ProductFeature
+		if err := rows.Scan(&feature.Id, &feature.ProductId, &feature.Label, &feature.Value, &feature.Included, &feature.Position, &feature.CreatedAt, &feature.UpdatedAt); err != nil {
+			return nil, err
+		}
+		features = append(features, feature)
+	}
+	return features, rows.Err()
+}
+
+// ambil :id produk dari path dan pastikan produknya ada
+func productFromPath(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+
+	var exists int
+	err = config.DB.QueryRow(`SELECT 1 FROM products WHERE id = $1`, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return 0, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return 0, false
+	}
+	return id, true
+}
+
+func bindProductFeature(c *gin.Context) (model.ProductFeatureRequest, bool) {
+	var req model.ProductFeatureRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return req, false
+	}
+
+	req.Label = strings.TrimSpace(req.Label)
+	req.Value = strings.TrimSpace(req.Value)
+	if req.Included == nil {
+		included := true
+		req.Included = &included
+	}
+	return req, true
+}
+
+// get fitur satu produk (admin)
+func GetProductFeatures(c *gin.Context) {
+	id, ok := productFromPath(c)
+	if !ok {
+		return
+	}
+
+	features, err := loadProductFeatures([]int{id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	if features == nil {
+		features = []model.ProductFeature{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": features})
+}
+
+// tambah fitur di urutan terakhir
+func CreateProductFeature(c *gin.Context) {
+	id, ok := productFromPath(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindProductFeature(c)
+	if !ok {
+		return
+	}
+
+	var featureID int
+	err := config.DB.QueryRow(`
+		INSERT INTO product_features (product_id, label, value, included, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position), 0) + 1 FROM product_features WHERE product_id = $1), $5, $6)
+		RETURNING id
+	`, id, req.Label, req.Value, *req.Included, time.Now(), time.Now()).Scan(&featureID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Data created successfully", "id": featureID})
+}
+
+// update fitur produk
+func UpdateProductFeature(c *gin.Context) {
+	id, ok := productFromPath(c)
+	if !ok {
+		return
+	}
+
+	featureID, err := strconv.Atoi(c.Param("feature_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feature ID"})
+		return
+	}
+
+	req, ok := bindProductFeature(c)
+	if !ok {
+		return
+	}
+
+	result, err := config.DB.Exec(`
+		UPDATE product_features
+		SET label = $1, value = $2, included = $3, updated_at = $4
+		WHERE id = $5 AND product_id = $6
+	`, req.Label, req.Value, *req.Included, time.Now(), featureID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
+// delete fitur produk
+func DeleteProductFeature(c *gin.Context) {
+	id, ok := productFromPath(c)
+	if !ok {
+		return
+	}
+
+	featureID, err := strconv.Atoi(c.Param("feature_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feature ID"})
+		return
+	}
+
+	result, err := config.DB.Exec(`DELETE FROM product_features WHERE id = $1 AND product_id = $2`, featureID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data deleted successfully"})
+}
+
+// ubah urutan fitur dalam satu produk
+func ReorderProductFeatures(c *gin.Context) {
+	id, ok := productFromPath(c)
+	if !ok {
+		return
+	}
+	reorderPositions(c, "product_features", "product_id", id)
+}
+
+// matriks perbandingan produk satu type untuk tabel harga
+func CompareProducts(c *gin.Context) {
+	productType := c.Query("type")
+	if productType == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type is required"})
+		return
+	}
+
+	products, err := loadProducts(requestLocale(c), productType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+
+	// baris = label fitur (case-insensitive), urut posisi terkecil lalu kemunculan pertama
+	rowIndex := map[string]int{}
+	positions := []int{}
+	rows := []model.ProductComparisonRow{}
+	for col, product := range products {
+		for _, feature := range product.Features {
+			key := strings.ToLower(feature.Label)
+			i, found := rowIndex[key]
+			if !found {
+				i = len(rows)
+				rowIndex[key] = i
+				rows = append(rows, model.ProductComparisonRow{
+					Label:  feature.Label,
+					Values: make([]*model.ProductComparisonCell, len(products)),
+				})
+				positions = append(positions, feature.Position)
+			} else if feature.Position < positions[i] {
+				positions[i] = feature.Position
+			}
+			rows[i].Values[col] = &model.ProductComparisonCell{Included: feature.Included, Value: feature.Value}
+		}
+	}
+
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return positions[order[a]] < positions[order[b]] })
+
+	comparison := model.ProductComparison{
+		Type:     productType,
+		Products: products,
+		Features: make([]model.ProductComparisonRow, 0, len(rows)),
+	}
+	for _, i := range order {
+		comparison.Features = append(comparison.Features, rows[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comparison})
+}
@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// cache hasil trending & related, dibersihkan saat artikel/kategori berubah
+var articleCache = utils.NewCache(5 * time.Minute)
+
+const (
+	trendingWindowDays = 14
+	trendingHalfLife   = 3.0 // hari
+)
+
+// kata umum (id + en) yang tidak dipakai untuk mencari artikel terkait
+var stopWords = map[string]bool{
+	"dan": true, "yang": true, "untuk": true, "dengan": true, "dari": true, "ini": true,
+	"itu": true, "pada": true, "dalam": true, "atau": true, "juga": true, "akan": true,
+	"the": true, "and": true, "for": true, "with": true, "from": true, "this": true,
+	"that": true, "are": true, "you": true, "your": true, "how": true, "what": true,
+}
+
+func invalidateArticleCache() {
+	articleCache.Clear()
+}
+
+// ambil kata kunci dari judul + deskripsi artikel
+func extractTerms(text string, max int) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := map[string]bool{}
+	var terms []string
+	for _, w := range words {
+		if len([]rune(w)) < 3 || stopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+		if len(terms) >= max {
+			break
+		}
+	}
+	return terms
+}
+
+func parseLimit(c *gin.Context, def int) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(def)))
+	if err != nil || limit < 1 || limit > 50 {
+		return 0, false
+	}
+	return limit, true
+}
+
+// get artikel trending (skor views dengan peluruhan waktu)
+func GetTrendingArticles(c *gin.Context) {
+	limit, ok := parseLimit(c, 10)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	format, ok := articleOutputFormat(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected html, markdown or plain"})
+		return
+	}
+
+	locale := requestLocale(c)
+	cacheKey := fmt.Sprintf("trending:%d:%s:%s", limit, format, locale)
+	if cached, ok := articleCache.Get(cacheKey); ok {
+		c.JSON(http.StatusOK, gin.H{"data": cached})
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -(trendingWindowDays - 1))
+
+	// skor = sum(views * 0.5 ^ (umur hari / half-life))
+	rows, err := config.DB.Query(`
+		WITH v AS (`+analytics.ViewsInRangeSQL+`)
+		SELECT
+			a.id, a.title, a.slug, a.description, a.content_format, a.thumbnail, a.views,
+			a.created_at, a.updated_at,
+			u.name AS user_name,
+			c.category AS category_name,
+			(SELECT COUNT(*) FROM article_comments cm WHERE cm.article_id = a.id AND cm.status = 'approved') AS comment_count,
+			SUM(v.views) AS recent_views,
+			SUM(v.views * POWER(0.5, (CURRENT_DATE - v.day) / $3::float)) AS score
+		FROM v
+		JOIN articles a ON a.id = v.article_id
+		JOIN users u ON a.user_id = u.id
+		JOIN category_articles c ON a.category_id = c.id
+		GROUP BY a.id, u.name, c.category
+		ORDER BY score DESC, a.created_at DESC
+		LIMIT $4
+	`, from.Format(dateLayout), to.Format(dateLayout), trendingHalfLife, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	articles := []model.TrendingArticle{}
+	for rows.Next() {
+		var art model.TrendingArticle
+		if err := rows.Scan(
+			&art.Id,
+			&art.Title,
+			&art.Slug,
+			&art.Description,
+			&art.ContentFormat,
+			&art.Thumbnail,
+			&art.Views,
+			&art.CreatedAt,
+			&art.UpdatedAt,
+			&art.User,
+			&art.Category,
+			&art.CommentCount,
+			&art.RecentViews,
+			&art.Score,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		articles = append(articles, art)
+	}
+
+	refs := make([]*model.ResponseArticle, len(articles))
+	for i := range articles {
+		refs[i] = &articles[i].ResponseArticle
+	}
+	if err := translateArticles(locale, refs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	for _, art := range refs {
+		renderArticle(art, format)
+	}
+
+	articleCache.Set(cacheKey, articles)
+	c.JSON(http.StatusOK, gin.H{"data": articles})
+}
+
+// get artikel terkait (kategori sama, diurutkan berdasarkan kata yang sama)
+func GetRelatedArticles(c *gin.Context) {
+	slugParam := c.Param("slug")
+
+	limit, ok := parseLimit(c, 5)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	format, ok := articleOutputFormat(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected html, markdown or plain"})
+		return
+	}
+
+	locale := requestLocale(c)
+	cacheKey := fmt.Sprintf("related:%s:%d:%s:%s", slugParam, limit, format, locale)
+	if cached, ok := articleCache.Get(cacheKey); ok {
+		c.JSON(http.StatusOK, gin.H{"data": cached})
+		return
+	}
+
+	var article model.Article
+	err := config.DB.QueryRow(`
+		SELECT id, title, description, content_format, category_id FROM articles WHERE slug = $1
+	`, slugParam).Scan(&article.Id, &article.Title, &article.Description, &article.ContentFormat, &article.CategoryId)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	// query OR dari kata kunci, kosong berarti tidak ada ranking kata
+	plain := utils.RenderContent(article.Description, article.ContentFormat, utils.ContentFormatPlain)
+	terms := extractTerms(article.Title+" "+plain, 20)
+	tsQuery := strings.Join(terms, " | ")
+
+	rows, err := config.DB.Query(`
+		SELECT
+			a.id, a.title, a.slug, a.description, a.content_format, a.thumbnail, a.views,
+			a.created_at, a.updated_at,
+			u.name AS user_name,
+			c.category AS category_name,
+			(SELECT COUNT(*) FROM article_comments cm WHERE cm.article_id = a.id AND cm.status = 'approved') AS comment_count
+		FROM articles a
+		JOIN users u ON a.user_id = u.id
+		JOIN category_articles c ON a.category_id = c.id
+		WHERE a.category_id = $1 AND a.id <> $2
+		ORDER BY
+			CASE WHEN $3 = '' THEN 0
+			ELSE ts_rank(to_tsvector('simple', a.title || ' ' || a.description), to_tsquery('simple', $3))
+			END DESC,
+			a.views DESC,
+			a.created_at DESC
+		LIMIT $4
+	`, article.CategoryId, article.Id, tsQuery, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	articles := []model.ResponseArticle{}
+	for rows.Next() {
+		var art model.ResponseArticle
+		if err := rows.Scan(
+			&art.Id,
+			&art.Title,
+			&art.Slug,
+			&art.Description,
+			&art.ContentFormat,
+			&art.Thumbnail,
+			&art.Views,
+			&art.CreatedAt,
+			&art.UpdatedAt,
+			&art.User,
+			&art.Category,
+			&art.CommentCount,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		articles = append(articles, art)
+	}
+
+	refs := make([]*model.ResponseArticle, len(articles))
+	for i := range articles {
+		refs[i] = &articles[i]
+	}
+	if err := translateArticles(locale, refs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	for _, art := range refs {
+		renderArticle(art, format)
+	}
+
+	articleCache.Set(cacheKey, articles)
+	c.JSON(http.StatusOK, gin.H{"data": articles})
+}
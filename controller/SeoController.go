@@ -0,0 +1,312 @@
+package controller
+
+import (
+	"database/sql"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const seoSelectColumns = `meta_title, meta_description, canonical_url, og_image, noindex`
+
+// hapus file upload lama (jika ada)
+func removeUploadedFile(path string) {
+	if path == "" {
+		return
+	}
+	oldFilePath := filepath.Join("uploads", filepath.Base(path))
+	if _, err := os.Stat(oldFilePath); err == nil {
+		_ = os.Remove(oldFilePath)
+	}
+}
+
+// ambil field SEO dari form, og_image bisa di-upload atau dihapus dengan remove_og_image=1
+func seoFromRequest(c *gin.Context, req model.SeoRequest, oldOgImage string) (model.SeoFields, error) {
+	seo := model.SeoFields{
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+		CanonicalUrl:    req.CanonicalUrl,
+		OgImage:         oldOgImage,
+		Noindex:         req.Noindex,
+	}
+
+	file, err := c.FormFile("og_image")
+	if err == nil {
+		if err := os.MkdirAll("uploads", os.ModePerm); err != nil {
+			return seo, err
+		}
+
+		filename := uuid.New().String() + filepath.Ext(file.Filename)
+		savePath := filepath.Join("uploads", filename)
+		if err := c.SaveUploadedFile(file, savePath); err != nil {
+			return seo, err
+		}
+
+		removeUploadedFile(oldOgImage)
+		seo.OgImage = "/uploads/" + filename
+	} else if c.PostForm("remove_og_image") == "1" {
+		removeUploadedFile(oldOgImage)
+		seo.OgImage = ""
+	}
+
+	return seo, nil
+}
+
+// isi metadata SEO final dengan default dari field konten
+func resolveSeo(c *gin.Context, seo model.SeoFields, title, plainDescription, fallbackImage, path, ogType string) model.SeoMeta {
+	meta := model.SeoMeta{
+		Title:        seo.MetaTitle,
+		Description:  seo.MetaDescription,
+		CanonicalUrl: seo.CanonicalUrl,
+		OgImage:      seo.OgImage,
+		OgType:       ogType,
+		Robots:       "index, follow",
+		Noindex:      seo.Noindex,
+	}
+
+	if meta.Title == "" {
+		meta.Title = title + " | " + config.SiteName
+	}
+	if meta.Description == "" {
+		meta.Description = utils.Excerpt(plainDescription, 160)
+	}
+	if meta.CanonicalUrl == "" {
+		meta.CanonicalUrl = siteURL(path)
+	}
+	if meta.OgImage == "" {
+		meta.OgImage = fallbackImage
+	}
+	meta.OgImage = absoluteURL(meta.OgImage)
+	if seo.Noindex {
+		meta.Robots = "noindex, nofollow"
+	}
+
+	return meta
+}
+
+// JSON-LD Organization dari nama situs + data kontak pertama
+func organizationJSONLD() map[string]interface{} {
+	org := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "Organization",
+		"name":     config.SiteName,
+		"url":      config.SiteURL,
+	}
+
+	var contact model.Contact
+	err := config.DB.QueryRow(`
+		SELECT phone, email, address FROM contacts ORDER BY id ASC LIMIT 1
+	`).Scan(&contact.Phone, &contact.Email, &contact.Address)
+	if err == nil {
+		org["email"] = contact.Email
+		org["telephone"] = contact.Phone
+		org["address"] = contact.Address
+		org["contactPoint"] = map[string]interface{}{
+			"@type":       "ContactPoint",
+			"telephone":   contact.Phone,
+			"email":       contact.Email,
+			"contactType": "customer service",
+		}
+	}
+
+	return org
+}
+
+// JSON-LD FAQPage dari daftar faq
+func faqPageJSONLD(faqs []model.FaqResponse) map[string]interface{} {
+	questions := []map[string]interface{}{}
+	for _, faq := range faqs {
+		questions = append(questions, map[string]interface{}{
+			"@type": "Question",
+			"name":  faq.Question,
+			"acceptedAnswer": map[string]interface{}{
+				"@type": "Answer",
+				"text":  utils.SanitizeHTML(faq.Answer),
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"@context":   "https://schema.org",
+		"@type":      "FAQPage",
+		"mainEntity": questions,
+	}
+}
+
+// get detail page berdasarkan slug + metadata SEO
+func GetPageBySlug(c *gin.Context) {
+	var page model.Pages
+	var seo model.SeoFields
+
+	err := config.DB.QueryRow(`
+		SELECT id, title, slug, type, description, banner, created_at, updated_at, `+seoSelectColumns+`
+		FROM pages WHERE slug = $1
+	`, c.Param("slug")).Scan(
+		&page.Id, &page.Title, &page.Slug, &page.Type, &page.Description, &page.Banner, &page.CreatedAt, &page.UpdatedAt,
+		&seo.MetaTitle, &seo.MetaDescription, &seo.CanonicalUrl, &seo.OgImage, &seo.Noindex,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Page not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	page.Seo = &seo
+
+	translations, err := loadTranslations("pages", requestLocale(c), []int{page.Id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	translateField(translations[page.Id], "title", &page.Title)
+	translateField(translations[page.Id], "description", &page.Description)
+
+	meta := resolveSeo(c, seo, page.Title, utils.HTMLToPlainText(page.Description), page.Banner, "/"+page.Slug, "website")
+	jsonLD := []interface{}{organizationJSONLD()}
+
+	// halaman bertipe faq ikut menampilkan FAQPage
+	if page.Type == "faq" {
+		faqs, err := loadFaqs(requestLocale(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch faqs", "detail": err.Error()})
+			return
+		}
+		jsonLD = append(jsonLD, faqPageJSONLD(faqs))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    page,
+		"seo":     meta,
+		"json_ld": jsonLD,
+	})
+}
+
+// get detail service berdasarkan slug + metadata SEO
+func GetServiceBySlug(c *gin.Context) {
+	var service model.Service
+	var seo model.SeoFields
+
+	err := config.DB.QueryRow(`
+		SELECT id, title, slug, description, icon, created_at, updated_at, `+seoSelectColumns+`
+		FROM services WHERE slug = $1
+	`, c.Param("slug")).Scan(
+		&service.Id, &service.Title, &service.Slug, &service.Description, &service.Icon, &service.CreatedAt, &service.UpdatedAt,
+		&seo.MetaTitle, &seo.MetaDescription, &seo.CanonicalUrl, &seo.OgImage, &seo.Noindex,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	service.Seo = &seo
+
+	translations, err := loadTranslations("services", requestLocale(c), []int{service.Id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	translateField(translations[service.Id], "title", &service.Title)
+	translateField(translations[service.Id], "description", &service.Description)
+
+	plain := utils.HTMLToPlainText(service.Description)
+	meta := resolveSeo(c, seo, service.Title, plain, service.Icon, "/services/"+service.Slug, "website")
+
+	org := organizationJSONLD()
+	serviceLD := map[string]interface{}{
+		"@context":    "https://schema.org",
+		"@type":       "Service",
+		"name":        service.Title,
+		"description": meta.Description,
+		"url":         meta.CanonicalUrl,
+		"provider":    map[string]interface{}{"@type": "Organization", "name": config.SiteName, "url": config.SiteURL},
+	}
+	if meta.OgImage != "" {
+		serviceLD["image"] = meta.OgImage
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    service,
+		"seo":     meta,
+		"json_ld": []interface{}{org, serviceLD},
+	})
+}
+
+// get detail artikel berdasarkan slug + metadata SEO
+func GetArticleBySlug(c *gin.Context) {
+	format, ok := articleOutputFormat(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected html, markdown or plain"})
+		return
+	}
+
+	var art model.ResponseArticle
+	var seo model.SeoFields
+
+	err := config.DB.QueryRow(`
+		SELECT
+			a.id, a.title, a.slug, a.description, a.content_format, a.thumbnail, a.views,
+			a.created_at, a.updated_at,
+			u.name AS user_name,
+			c.category AS category_name,
+			(SELECT COUNT(*) FROM article_comments cm WHERE cm.article_id = a.id AND cm.status = 'approved') AS comment_count,
+			a.meta_title, a.meta_description, a.canonical_url, a.og_image, a.noindex
+		FROM articles a
+		JOIN users u ON a.user_id = u.id
+		JOIN category_articles c ON a.category_id = c.id
+		WHERE a.slug = $1
+	`, c.Param("slug")).Scan(
+		&art.Id, &art.Title, &art.Slug, &art.Description, &art.ContentFormat, &art.Thumbnail, &art.Views,
+		&art.CreatedAt, &art.UpdatedAt, &art.User, &art.Category, &art.CommentCount,
+		&seo.MetaTitle, &seo.MetaDescription, &seo.CanonicalUrl, &seo.OgImage, &seo.Noindex,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	if err := translateArticles(requestLocale(c), []*model.ResponseArticle{&art}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	renderArticle(&art, format)
+	art.Seo = &seo
+
+	meta := resolveSeo(c, seo, art.Title, art.Excerpt, art.Thumbnail, articlePathPrefix+art.Slug, "article")
+	meta.PublishedTime = art.CreatedAt.UTC().Format(time.RFC3339)
+	meta.ModifiedTime = art.UpdatedAt.UTC().Format(time.RFC3339)
+
+	articleLD := map[string]interface{}{
+		"@context":         "https://schema.org",
+		"@type":            "Article",
+		"headline":         art.Title,
+		"description":      meta.Description,
+		"datePublished":    meta.PublishedTime,
+		"dateModified":     meta.ModifiedTime,
+		"articleSection":   art.Category,
+		"mainEntityOfPage": map[string]interface{}{"@type": "WebPage", "@id": meta.CanonicalUrl},
+		"author":           map[string]interface{}{"@type": "Person", "name": art.User},
+		"publisher":        map[string]interface{}{"@type": "Organization", "name": config.SiteName, "url": config.SiteURL},
+	}
+	if meta.OgImage != "" {
+		articleLD["image"] = []string{meta.OgImage}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    art,
+		"seo":     meta,
+		"json_ld": []interface{}{articleLD, organizationJSONLD()},
+	})
+}
@@ -22,7 +22,7 @@ import (
 func GetAllServices(c *gin.Context) {
 	var services []model.Service
 
-	rows, err := config.DB.Query(`SELECT id, title, slug, description, icon, created_at, updated_at FROM services`)
+	rows, err := config.DB.Query(`SELECT id, title, slug, description, icon, created_at, updated_at, ` + seoSelectColumns + ` FROM services`)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
 		return
@@ -31,13 +31,30 @@ func GetAllServices(c *gin.Context) {
 
 	for rows.Next() {
 		var service model.Service
-		if err := rows.Scan(&service.Id, &service.Title, &service.Slug, &service.Description, &service.Icon, &service.CreatedAt, &service.UpdatedAt); err != nil {
+		var seo model.SeoFields
+		if err := rows.Scan(&service.Id, &service.Title, &service.Slug, &service.Description, &service.Icon, &service.CreatedAt, &service.UpdatedAt,
+			&seo.MetaTitle, &seo.MetaDescription, &seo.CanonicalUrl, &seo.OgImage, &seo.Noindex); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan data", "detail": err.Error()})
 			return
 		}
+		service.Seo = &seo
 		services = append(services, service)
 	}
 
+	ids := make([]int, len(services))
+	for i := range services {
+		ids[i] = services[i].Id
+	}
+	translations, err := loadTranslations("services", requestLocale(c), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	for i := range services {
+		translateField(translations[services[i].Id], "title", &services[i].Title)
+		translateField(translations[services[i].Id], "description", &services[i].Description)
+	}
+
 	if len(services) == 0 {
 		c.JSON(http.StatusOK, gin.H{"data": []interface{}{}})
 		return
@@ -85,11 +102,20 @@ func CreateService(c *gin.Context) {
 
 	icon := "/uploads/" + filename
 
+	// Metadata SEO (og_image opsional)
+	seo, err := seoFromRequest(c, req.SeoRequest, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload og image"})
+		return
+	}
+
 	query := `
-		INSERT INTO services (title, slug, description, icon, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO services (title, slug, description, icon, created_at, updated_at,
+			meta_title, meta_description, canonical_url, og_image, noindex)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	_, err = config.DB.Exec(query, title, slug.Make(title), description, icon, time.Now(), time.Now())
+	_, err = config.DB.Exec(query, title, slug.Make(title), description, icon, time.Now(), time.Now(),
+		seo.MetaTitle, seo.MetaDescription, seo.CanonicalUrl, seo.OgImage, seo.Noindex)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
 		return
@@ -125,8 +151,8 @@ func UpdateService(c *gin.Context) {
 	title := c.PostForm("title")
 	description := c.PostForm("description")
 
-	var oldIcon string
-	err = config.DB.QueryRow("SELECT icon FROM services WHERE id = $1", id).Scan(&oldIcon)
+	var oldIcon, oldOgImage string
+	err = config.DB.QueryRow("SELECT icon, og_image FROM services WHERE id = $1", id).Scan(&oldIcon, &oldOgImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
 		return
@@ -161,13 +187,22 @@ func UpdateService(c *gin.Context) {
 		iconPath = "/uploads/" + filename
 	}
 
+	// Metadata SEO
+	seo, err := seoFromRequest(c, req.SeoRequest, oldOgImage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload og image"})
+		return
+	}
+
 	// Update ke DB
 	query := `
 		UPDATE services
-		SET title = $1, slug = $2, description = $3, icon = $4, updated_at = $5
-		WHERE id = $6
+		SET title = $1, slug = $2, description = $3, icon = $4, updated_at = $5,
+			meta_title = $6, meta_description = $7, canonical_url = $8, og_image = $9, noindex = $10
+		WHERE id = $11
 	`
-	_, err = config.DB.Exec(query, title, slug.Make(title), description, iconPath, time.Now(), id)
+	_, err = config.DB.Exec(query, title, slug.Make(title), description, iconPath, time.Now(),
+		seo.MetaTitle, seo.MetaDescription, seo.CanonicalUrl, seo.OgImage, seo.Noindex, id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
 		return
@@ -185,8 +220,8 @@ func DeleteService(c *gin.Context) {
 		return
 	}
 
-	var oldIcon string
-	err = config.DB.QueryRow("SELECT icon FROM services WHERE id = $1", id).Scan(&oldIcon)
+	var oldIcon, oldOgImage string
+	err = config.DB.QueryRow("SELECT icon, og_image FROM services WHERE id = $1", id).Scan(&oldIcon, &oldOgImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
 		return
@@ -211,5 +246,8 @@ func DeleteService(c *gin.Context) {
 		}
 	}
 
+	removeUploadedFile(oldOgImage)
+	deleteTranslations("services", id)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Data deleted successfully"})
 }
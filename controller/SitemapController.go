@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"database/sql"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gin-gonic/gin"
+)
+
+// batas protokol sitemap: maksimal 50.000 URL per file
+var sitemapChunkSize = 50000
+
+type sitemapSection struct {
+	name       string
+	countSQL   string
+	listSQL    string // hasil (key, lastmod), parameter $1 limit, $2 offset
+	path       func(key string) string
+	changeFreq string
+	priority   string
+}
+
+var sitemapSections = []sitemapSection{
+	{
+		name:       "pages",
+		countSQL:   `SELECT COUNT(*) FROM pages WHERE noindex = FALSE`,
+		listSQL:    `SELECT slug, updated_at FROM pages WHERE noindex = FALSE ORDER BY id LIMIT $1 OFFSET $2`,
+		path:       func(key string) string { return "/" + key },
+		changeFreq: "monthly",
+		priority:   "0.8",
+	},
+	{
+		name:       "services",
+		countSQL:   `SELECT COUNT(*) FROM services WHERE noindex = FALSE`,
+		listSQL:    `SELECT slug, updated_at FROM services WHERE noindex = FALSE ORDER BY id LIMIT $1 OFFSET $2`,
+		path:       func(key string) string { return "/services/" + key },
+		changeFreq: "monthly",
+		priority:   "0.7",
+	},
+	{
+		name:     "categories",
+		countSQL: `SELECT COUNT(*) FROM category_articles`,
+		// lastmod kategori mengikuti artikel terbaru di dalamnya
+		listSQL: `
+			SELECT c.slug, GREATEST(c.updated_at, COALESCE(MAX(a.updated_at), c.updated_at))
+			FROM category_articles c
+			LEFT JOIN articles a ON a.category_id = c.id
+			GROUP BY c.id, c.slug, c.updated_at
+			ORDER BY c.id
+			LIMIT $1 OFFSET $2`,
+		path:       func(key string) string { return articlePathPrefix + "category/" + key },
+		changeFreq: "weekly",
+		priority:   "0.5",
+	},
+	{
+		name:       "articles",
+		countSQL:   `SELECT COUNT(*) FROM articles WHERE noindex = FALSE`,
+		listSQL:    `SELECT slug, updated_at FROM articles WHERE noindex = FALSE ORDER BY id LIMIT $1 OFFSET $2`,
+		path:       func(key string) string { return articlePathPrefix + key },
+		changeFreq: "weekly",
+		priority:   "0.6",
+	},
+}
+
+var sitemapFilePattern = regexp.MustCompile(`^([a-z]+)-(\d+)\.xml$`)
+
+func siteURL(path string) string {
+	return strings.TrimRight(config.SiteURL, "/") + path
+}
+
+// ambil URL satu section untuk chunk tertentu (dimulai dari 1)
+func sitemapChunk(section sitemapSection, chunk int) ([]model.SitemapURL, error) {
+	rows, err := config.DB.Query(section.listSQL, sitemapChunkSize, (chunk-1)*sitemapChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []model.SitemapURL
+	for rows.Next() {
+		var key string
+		var lastMod time.Time
+		if err := rows.Scan(&key, &lastMod); err != nil {
+			return nil, err
+		}
+		urls = append(urls, model.SitemapURL{
+			Loc:        siteURL(section.path(key)),
+			LastMod:    lastMod.UTC().Format(time.RFC3339),
+			ChangeFreq: section.changeFreq,
+			Priority:   section.priority,
+		})
+	}
+	return urls, rows.Err()
+}
+
+type sitemapPart struct {
+	section sitemapSection
+	chunk   int
+}
+
+// daftar file sitemap per section sesuai jumlah data
+func sitemapParts() ([]sitemapPart, int, error) {
+	var parts []sitemapPart
+	total := 0
+
+	for _, section := range sitemapSections {
+		var count int
+		if err := config.DB.QueryRow(section.countSQL).Scan(&count); err != nil {
+			return nil, 0, err
+		}
+		total += count
+
+		chunks := (count + sitemapChunkSize - 1) / sitemapChunkSize
+		for i := 1; i <= chunks; i++ {
+			parts = append(parts, sitemapPart{section: section, chunk: i})
+		}
+	}
+	return parts, total, nil
+}
+
+func sitemapHome() model.SitemapURL {
+	return model.SitemapURL{Loc: siteURL("/"), ChangeFreq: "daily", Priority: "1.0"}
+}
+
+// sitemap utama: urlset jika muat satu file, selain itu sitemap index
+func GetSitemap(c *gin.Context) {
+	parts, total, err := sitemapParts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate sitemap", "detail": err.Error()})
+		return
+	}
+
+	if total+1 > sitemapChunkSize {
+		writeSitemapIndex(c, parts)
+		return
+	}
+
+	urlSet := model.SitemapURLSet{URLs: []model.SitemapURL{sitemapHome()}}
+	for _, part := range parts {
+		urls, err := sitemapChunk(part.section, part.chunk)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate sitemap", "detail": err.Error()})
+			return
+		}
+		urlSet.URLs = append(urlSet.URLs, urls...)
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	writeXML(c, "application/xml; charset=utf-8", urlSet)
+}
+
+// sitemap index yang selalu dipecah per section / chunk
+func GetSitemapIndex(c *gin.Context) {
+	parts, _, err := sitemapParts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate sitemap", "detail": err.Error()})
+		return
+	}
+	writeSitemapIndex(c, parts)
+}
+
+func writeSitemapIndex(c *gin.Context, parts []sitemapPart) {
+	base := config.APIURL
+	index := model.SitemapIndex{
+		Sitemaps: []model.SitemapRef{{Loc: base + "/sitemaps/static-1.xml"}},
+	}
+	for _, part := range parts {
+		index.Sitemaps = append(index.Sitemaps, model.SitemapRef{
+			Loc: base + "/sitemaps/" + part.section.name + "-" + strconv.Itoa(part.chunk) + ".xml",
+		})
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	writeXML(c, "application/xml; charset=utf-8", index)
+}
+
+// satu file sitemap, contoh /sitemaps/articles-2.xml
+func GetSitemapFile(c *gin.Context) {
+	m := sitemapFilePattern.FindStringSubmatch(c.Param("file"))
+	if m == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+	chunk, _ := strconv.Atoi(m[2])
+
+	urlSet := model.SitemapURLSet{URLs: []model.SitemapURL{}}
+
+	if m[1] == "static" && chunk == 1 {
+		urlSet.URLs = append(urlSet.URLs, sitemapHome())
+	} else {
+		var section *sitemapSection
+		for i := range sitemapSections {
+			if sitemapSections[i].name == m[1] {
+				section = &sitemapSections[i]
+			}
+		}
+		if section == nil || chunk < 1 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+			return
+		}
+
+		urls, err := sitemapChunk(*section, chunk)
+		if err != nil && err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate sitemap", "detail": err.Error()})
+			return
+		}
+		if len(urls) == 0 && chunk > 1 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+			return
+		}
+		urlSet.URLs = append(urlSet.URLs, urls...)
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	writeXML(c, "application/xml; charset=utf-8", urlSet)
+}
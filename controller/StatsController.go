@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gin-gonic/gin"
+)
+
+const dateLayout = "2006-01-02"
+
+// parse ?from=YYYY-MM-DD&to=YYYY-MM-DD, default 30 hari terakhir
+func parseDateRange(c *gin.Context) (time.Time, time.Time, string) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -29)
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(dateLayout, v)
+		if err != nil {
+			return from, to, "Invalid from date, expected YYYY-MM-DD"
+		}
+		from = t
+	}
+
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(dateLayout, v)
+		if err != nil {
+			return from, to, "Invalid to date, expected YYYY-MM-DD"
+		}
+		to = t
+	}
+
+	if from.After(to) {
+		return from, to, "from must be before to"
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return from, to, "Date range cannot exceed 366 days"
+	}
+
+	return from, to, ""
+}
+
+// get statistik dashboard admin
+func GetDashboardStats(c *gin.Context) {
+	from, to, msg := parseDateRange(c)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	fromStr, toStr := from.Format(dateLayout), to.Format(dateLayout)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	stats := model.StatsResponse{
+		From:               fromStr,
+		To:                 toStr,
+		TopArticles:        []model.TopArticle{},
+		ViewsPerDay:        []model.ViewsPerDay{},
+		ArticlesByCategory: []model.CountByLabel{},
+		ArticlesByAuthor:   []model.CountByLabel{},
+		NewestUsers:        []model.UserResponse{},
+		GeneratedAt:        time.Now(),
+	}
+
+	// total data per resource
+	err = config.DB.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM pages),
+			(SELECT COUNT(*) FROM abouts),
+			(SELECT COUNT(*) FROM services),
+			(SELECT COUNT(*) FROM portfolios),
+			(SELECT COUNT(*) FROM products),
+			(SELECT COUNT(*) FROM contacts),
+			(SELECT COUNT(*) FROM users),
+			(SELECT COUNT(*) FROM category_articles),
+			(SELECT COUNT(*) FROM articles),
+			(SELECT COUNT(*) FROM category_faqs),
+			(SELECT COUNT(*) FROM faqs),
+			(SELECT COALESCE(SUM(views), 0) FROM articles)
+	`).Scan(
+		&stats.Totals.Pages,
+		&stats.Totals.Abouts,
+		&stats.Totals.Services,
+		&stats.Totals.Portfolios,
+		&stats.Totals.Products,
+		&stats.Totals.Contacts,
+		&stats.Totals.Users,
+		&stats.Totals.CategoryArticles,
+		&stats.Totals.Articles,
+		&stats.Totals.CategoryFaqs,
+		&stats.Totals.Faqs,
+		&stats.Totals.ArticleViewsTotal,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch totals", "detail": err.Error()})
+		return
+	}
+
+	// artikel terpopuler dalam rentang tanggal
+	rows, err := config.DB.Query(`
+		WITH v AS (`+analytics.ViewsInRangeSQL+`)
+		SELECT a.id, a.title, a.slug, c.category, SUM(v.views) AS total
+		FROM v
+		JOIN articles a ON a.id = v.article_id
+		JOIN category_articles c ON a.category_id = c.id
+		GROUP BY a.id, a.title, a.slug, c.category
+		ORDER BY total DESC
+		LIMIT $3
+	`, fromStr, toStr, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch top articles", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var top model.TopArticle
+		if err := rows.Scan(&top.Id, &top.Title, &top.Slug, &top.Category, &top.Views); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		stats.TopArticles = append(stats.TopArticles, top)
+	}
+
+	// time-series views per hari (hari tanpa view tetap muncul dengan nilai 0)
+	dayRows, err := config.DB.Query(`
+		WITH v AS (`+analytics.ViewsInRangeSQL+`)
+		SELECT to_char(d.day, 'YYYY-MM-DD'), COALESCE(SUM(v.views), 0)
+		FROM generate_series($1::date, $2::date, interval '1 day') AS d(day)
+		LEFT JOIN v ON v.day = d.day::date
+		GROUP BY d.day
+		ORDER BY d.day
+	`, fromStr, toStr)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch views per day", "detail": err.Error()})
+		return
+	}
+	defer dayRows.Close()
+
+	for dayRows.Next() {
+		var day model.ViewsPerDay
+		if err := dayRows.Scan(&day.Day, &day.Views); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		stats.ViewsPerDay = append(stats.ViewsPerDay, day)
+	}
+
+	// jumlah artikel per kategori
+	categoryRows, err := config.DB.Query(`
+		SELECT c.id, c.category, COUNT(a.id)
+		FROM category_articles c
+		LEFT JOIN articles a ON a.category_id = c.id
+		GROUP BY c.id, c.category
+		ORDER BY COUNT(a.id) DESC, c.category ASC
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch articles per category", "detail": err.Error()})
+		return
+	}
+	defer categoryRows.Close()
+
+	for categoryRows.Next() {
+		var row model.CountByLabel
+		if err := categoryRows.Scan(&row.Id, &row.Label, &row.Total); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		stats.ArticlesByCategory = append(stats.ArticlesByCategory, row)
+	}
+
+	// jumlah artikel per penulis
+	authorRows, err := config.DB.Query(`
+		SELECT u.id, u.name, COUNT(a.id)
+		FROM users u
+		JOIN articles a ON a.user_id = u.id
+		GROUP BY u.id, u.name
+		ORDER BY COUNT(a.id) DESC, u.name ASC
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch articles per author", "detail": err.Error()})
+		return
+	}
+	defer authorRows.Close()
+
+	for authorRows.Next() {
+		var row model.CountByLabel
+		if err := authorRows.Scan(&row.Id, &row.Label, &row.Total); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		stats.ArticlesByAuthor = append(stats.ArticlesByAuthor, row)
+	}
+
+	// user terbaru
+	userRows, err := config.DB.Query(`
+		SELECT id, name, email, profile, role, created_at, updated_at
+		FROM users
+		ORDER BY created_at DESC
+		LIMIT 5
+	`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch newest users", "detail": err.Error()})
+		return
+	}
+	defer userRows.Close()
+
+	for userRows.Next() {
+		var user model.UserResponse
+		if err := userRows.Scan(&user.Id, &user.Name, &user.Email, &user.Profile, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+		stats.NewestUsers = append(stats.NewestUsers, user)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": stats})
+}
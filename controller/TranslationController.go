@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// field yang bisa diterjemahkan per resource (nama resource = nama tabel)
+var translatableFields = map[string][]string{
+	"pages":    {"title", "description"},
+	"abouts":   {"title", "description"},
+	"services": {"title", "description"},
+	"products": {"title", "description"},
+	"faqs":     {"question", "answer"},
+	"articles": {"title", "description"},
+}
+
+// locale dari middleware Locale, route admin selalu memakai locale default
+func requestLocale(c *gin.Context) string {
+	if locale := c.GetString("locale"); locale != "" {
+		return locale
+	}
+	return config.DefaultLocale
+}
+
+// ambil terjemahan beberapa baris sekaligus: map[resource_id]map[field]value
+func loadTranslations(resource, locale string, ids []int) (map[int]map[string]string, error) {
+	result := map[int]map[string]string{}
+	if locale == config.DefaultLocale || len(ids) == 0 {
+		return result, nil
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT resource_id, field, value
+		FROM translations
+		WHERE resource = $1 AND locale = $2 AND resource_id = ANY($3)
+	`, resource, locale, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var field, value string
+		if err := rows.Scan(&id, &field, &value); err != nil {
+			return nil, err
+		}
+		if result[id] == nil {
+			result[id] = map[string]string{}
+		}
+		result[id][field] = value
+	}
+	return result, rows.Err()
+}
+
+// timpa nilai field jika terjemahannya ada, selain itu tetap locale default
+func translateField(fields map[string]string, field string, dst *string) {
+	if v := fields[field]; v != "" {
+		*dst = v
+	}
+}
+
+// terjemahkan judul + deskripsi artikel sebelum di-render
+func translateArticles(locale string, articles []*model.ResponseArticle) error {
+	ids := make([]int, len(articles))
+	for i, art := range articles {
+		ids[i] = art.Id
+	}
+
+	translations, err := loadTranslations("articles", locale, ids)
+	if err != nil {
+		return err
+	}
+	for _, art := range articles {
+		translateField(translations[art.Id], "title", &art.Title)
+		translateField(translations[art.Id], "description", &art.Description)
+	}
+	return nil
+}
+
+// hapus semua terjemahan milik satu baris (dipanggil saat data dihapus)
+func deleteTranslations(resource string, id int) {
+	_, _ = config.DB.Exec(`DELETE FROM translations WHERE resource = $1 AND resource_id = $2`, resource, id)
+}
+
+// validasi parameter :resource dan :id, pastikan datanya ada
+func translationTarget(c *gin.Context) (string, int, bool) {
+	resource := c.Param("resource")
+	if _, ok := translatableFields[resource]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource"})
+		return "", 0, false
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return "", 0, false
+	}
+
+	// nama tabel aman karena sudah dicek di translatableFields
+	var exists int
+	err = config.DB.QueryRow(`SELECT 1 FROM `+resource+` WHERE id = $1`, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return "", 0, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return "", 0, false
+	}
+
+	return resource, id, true
+}
+
+// get semua terjemahan satu data, dikelompokkan per locale
+func GetTranslations(c *gin.Context) {
+	resource, id, ok := translationTarget(c)
+	if !ok {
+		return
+	}
+
+	rows, err := config.DB.Query(`
+		SELECT locale, field, value, updated_at
+		FROM translations
+		WHERE resource = $1 AND resource_id = $2
+		ORDER BY locale ASC, field ASC
+	`, resource, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	translations := []model.TranslationResponse{}
+	index := map[string]int{}
+	for rows.Next() {
+		var locale, field, value string
+		var updatedAt time.Time
+		if err := rows.Scan(&locale, &field, &value, &updatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse data", "detail": err.Error()})
+			return
+		}
+
+		i, found := index[locale]
+		if !found {
+			i = len(translations)
+			index[locale] = i
+			translations = append(translations, model.TranslationResponse{Locale: locale, Fields: map[string]string{}})
+		}
+		translations[i].Fields[field] = value
+		if updatedAt.After(translations[i].UpdatedAt) {
+			translations[i].UpdatedAt = updatedAt
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":              translations,
+		"fields":            translatableFields[resource],
+		"default_locale":    config.DefaultLocale,
+		"supported_locales": config.SupportedLocales,
+	})
+}
+
+// simpan terjemahan satu locale, field yang dikirim kosong dihapus
+func UpsertTranslation(c *gin.Context) {
+	resource, id, ok := translationTarget(c)
+	if !ok {
+		return
+	}
+
+	locale := strings.ToLower(c.Param("locale"))
+	if !config.IsSupportedLocale(locale) || locale == config.DefaultLocale {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid locale"})
+		return
+	}
+
+	// deskripsi artikel disanitasi sesuai format artikel aslinya
+	contentFormat := ""
+	if resource == "articles" {
+		if err := config.DB.QueryRow(`SELECT content_format FROM articles WHERE id = $1`, id).Scan(&contentFormat); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return
+		}
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	defer tx.Rollback()
+
+	updated := 0
+	for _, field := range translatableFields[resource] {
+		value, sent := c.GetPostForm(field)
+		if !sent {
+			continue
+		}
+		updated++
+
+		value = strings.TrimSpace(value)
+		if value != "" && contentFormat != "" && field == "description" {
+			value = utils.SanitizeContent(value, contentFormat)
+		}
+
+		if value == "" {
+			_, err = tx.Exec(`
+				DELETE FROM translations WHERE resource = $1 AND resource_id = $2 AND locale = $3 AND field = $4
+			`, resource, id, locale, field)
+		} else {
+			_, err = tx.Exec(`
+				INSERT INTO translations (resource, resource_id, locale, field, value, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $6)
+				ON CONFLICT (resource, resource_id, locale, field)
+				DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+			`, resource, id, locale, field, value, time.Now())
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+			return
+		}
+	}
+
+	if updated == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No translatable fields sent", "fields": translatableFields[resource]})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	if resource == "articles" {
+		invalidateArticleCache()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
+// hapus semua terjemahan satu locale
+func DeleteTranslation(c *gin.Context) {
+	resource, id, ok := translationTarget(c)
+	if !ok {
+		return
+	}
+
+	result, err := config.DB.Exec(`
+		DELETE FROM translations WHERE resource = $1 AND resource_id = $2 AND locale = $3
+	`, resource, id, strings.ToLower(c.Param("locale")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	}
+
+	if resource == "articles" {
+		invalidateArticleCache()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data deleted successfully"})
+}
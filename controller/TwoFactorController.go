@@ -0,0 +1,528 @@
+package controller
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func bindTwoFactorRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBind(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return false
+	}
+	return true
+}
+
+// token langkah kedua login = <id baris two_factor_challenges>.<rahasia>.<tanda tangan>,
+// bukan JWT sehingga tidak pernah diterima AuthMiddleware sebagai sesi
+func twoFactorChallenge(userID, tokenVersion int) (string, error) {
+	now := time.Now()
+	_, _ = config.DB.Exec(`DELETE FROM two_factor_challenges WHERE user_id = $1 AND expires_at < $2`, userID, now)
+
+	secret := utils.RandomToken(32)
+	var id int
+	err := config.DB.QueryRow(`
+		INSERT INTO two_factor_challenges (user_id, token_hash, token_version, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, userID, utils.HashToken(secret), tokenVersion, now.Add(config.TwoFactorChallengeTTL), now).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	return signIDToken(id, secret), nil
+}
+
+// challenge yang masih berlaku: belum dipakai, belum kadaluarsa, percobaan belum habis
+func parseTwoFactorChallenge(token string) (id, userID, tokenVersion int, ok bool, err error) {
+	id, secret, valid := parseIDToken(token)
+	if !valid {
+		return 0, 0, 0, false, nil
+	}
+
+	err = config.DB.QueryRow(`
+		SELECT user_id, token_version FROM two_factor_challenges
+		WHERE id = $1 AND token_hash = $2 AND used_at IS NULL AND expires_at > $3 AND attempts < $4
+	`, id, utils.HashToken(secret), time.Now(), config.TwoFactorChallengeMaxAttempts).Scan(&userID, &tokenVersion)
+	if err == sql.ErrNoRows {
+		return 0, 0, 0, false, nil
+	} else if err != nil {
+		return 0, 0, 0, false, err
+	}
+	return id, userID, tokenVersion, true, nil
+}
+
+var (
+	totpCodePattern     = regexp.MustCompile(`^[0-9]{6}$`)
+	recoveryCodePattern = regexp.MustCompile(`^[a-z2-7]{10}$`)
+)
+
+// recovery code: 10 karakter base32 huruf kecil, ditampilkan sebagai xxxxx-xxxxx
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+// buat ulang recovery code (yang lama tidak berlaku), return kode asli untuk ditampilkan sekali
+func generateRecoveryCodes(tx *sql.Tx, userID int) ([]string, error) {
+	if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return nil, err
+	}
+
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, config.RecoveryCodeCount)
+	for i := 0; i < config.RecoveryCodeCount; i++ {
+		b := make([]byte, 7)
+		rand.Read(b)
+		code := strings.ToLower(encoding.EncodeToString(b))[:10]
+
+		hash, err := utils.HashPassword(code)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO user_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)
+		`, userID, hash, time.Now()); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+	return codes, nil
+}
+
+// verifikasi kode TOTP atau recovery code, return metode yang dipakai ("" jika gagal).
+// kode TOTP yang sudah pernah dipakai dan recovery code bekas ditolak
+func verifySecondFactor(userID int, secret, code string) (string, error) {
+	code = strings.TrimSpace(code)
+
+	var lastStep int64
+	if err := config.DB.QueryRow(`SELECT totp_last_step FROM users WHERE id = $1`, userID).Scan(&lastStep); err != nil {
+		return "", err
+	}
+
+	// UPDATE bersyarat tetap dipakai agar request paralel dengan kode yang sama hanya lolos satu
+	if step, ok := utils.ValidateTOTP(secret, code, time.Now(), lastStep); ok {
+		result, err := config.DB.Exec(`
+			UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1
+		`, step, userID)
+		if err != nil {
+			return "", err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return "", nil
+		}
+		return "totp", nil
+	}
+
+	// input 6 digit adalah kode TOTP; recovery code dicek (bcrypt, mahal) hanya jika
+	// formatnya cocok
+	if totpCodePattern.MatchString(code) {
+		return "", nil
+	}
+	normalized := normalizeRecoveryCode(code)
+	if !recoveryCodePattern.MatchString(normalized) {
+		return "", nil
+	}
+
+	rows, err := config.DB.Query(`SELECT id, code_hash FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	matchedID := 0
+	for rows.Next() {
+		var id int
+		var hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			return "", err
+		}
+		if utils.CheckPasswordHash(normalized, hash) {
+			matchedID = id
+			break
+		}
+	}
+	rows.Close()
+	if matchedID == 0 {
+		return "", nil
+	}
+
+	result, err := config.DB.Exec(`UPDATE user_recovery_codes SET used_at = $1 WHERE id = $2 AND used_at IS NULL`, time.Now(), matchedID)
+	if err != nil {
+		return "", err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return "", nil
+	}
+	return "recovery_code", nil
+}
+
+func remainingRecoveryCodes(userID int) int {
+	var remaining int
+	_ = config.DB.QueryRow(`SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&remaining)
+	return remaining
+}
+
+type twoFactorUser struct {
+	email   string
+	role    string
+	secret  string
+	enabled bool
+}
+
+func loadTwoFactorUser(c *gin.Context, userID int) (twoFactorUser, bool) {
+	var u twoFactorUser
+	var secret sql.NullString
+	err := config.DB.QueryRow(`
+		SELECT email, role, totp_secret, totp_enabled_at IS NOT NULL FROM users WHERE id = $1
+	`, userID).Scan(&u.email, &u.role, &secret, &u.enabled)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return u, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return u, false
+	}
+	u.secret = secret.String
+	return u, true
+}
+
+// status 2FA user yang sedang login
+func GetTwoFactorStatus(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var status model.TwoFactorStatus
+	var role string
+	var enabledAt sql.NullTime
+	err := config.DB.QueryRow(`SELECT role, totp_enabled_at FROM users WHERE id = $1`, userID).Scan(&role, &enabledAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	status.Required = config.TwoFactorRequired(role)
+	if enabledAt.Valid {
+		status.Enabled = true
+		status.EnabledAt = &enabledAt.Time
+		status.RecoveryCodesRemaining = remainingRecoveryCodes(userID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// buat secret baru, belum aktif sampai dikonfirmasi lewat /2fa/enable
+func SetupTwoFactor(c *gin.Context) {
+	userID := c.GetInt("user_id")
+	u, ok := loadTwoFactorUser(c, userID)
+	if !ok {
+		return
+	}
+	if u.enabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret := utils.GenerateTOTPSecret()
+	if _, err := config.DB.Exec(`UPDATE users SET totp_secret = $1, updated_at = $2 WHERE id = $3`, secret, time.Now(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": model.TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(config.SiteName, u.email, secret),
+	}})
+}
+
+// aktifkan 2FA dengan kode pertama dari aplikasi authenticator, recovery code
+// hanya ditampilkan sekali di response ini
+func EnableTwoFactor(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var req model.TwoFactorCodeRequest
+	if !bindTwoFactorRequest(c, &req) {
+		return
+	}
+
+	u, ok := loadTwoFactorUser(c, userID)
+	if !ok {
+		return
+	}
+	if u.enabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+	if u.secret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication has not been set up"})
+		return
+	}
+
+	step, valid := utils.ValidateTOTP(u.secret, req.Code, time.Now(), 0)
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid authentication code"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE users SET totp_enabled_at = $1, totp_last_step = $2, updated_at = $1 WHERE id = $3
+	`, time.Now(), step, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	codes, err := generateRecoveryCodes(tx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recovery codes", "detail": err.Error()})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled successfully",
+		"recovery_codes": codes,
+	})
+}
+
+// nonaktifkan 2FA, butuh password + kode; tidak bisa untuk role yang wajib 2FA
+func DisableTwoFactor(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var req model.TwoFactorDisableRequest
+	if !bindTwoFactorRequest(c, &req) {
+		return
+	}
+
+	u, ok := loadTwoFactorUser(c, userID)
+	if !ok {
+		return
+	}
+	if !u.enabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+	if config.TwoFactorRequired(u.role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication is required for your role"})
+		return
+	}
+
+	var passwordHash string
+	if err := config.DB.QueryRow(`SELECT password FROM users WHERE id = $1`, userID).Scan(&passwordHash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if !utils.CheckPasswordHash(req.Password, passwordHash) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	method, err := verifySecondFactor(userID, u.secret, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if method == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid authentication code"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0, updated_at = $1 WHERE id = $2
+	`, time.Now(), userID)
+	if err == nil {
+		_, err = tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled successfully"})
+}
+
+// buat recovery code baru (yang lama hangus), butuh kode 2FA
+func RegenerateRecoveryCodes(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var req model.TwoFactorCodeRequest
+	if !bindTwoFactorRequest(c, &req) {
+		return
+	}
+
+	u, ok := loadTwoFactorUser(c, userID)
+	if !ok {
+		return
+	}
+	if !u.enabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	method, err := verifySecondFactor(userID, u.secret, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if method == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid authentication code"})
+		return
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	defer tx.Rollback()
+
+	codes, err := generateRecoveryCodes(tx, userID)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recovery codes", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Recovery codes regenerated successfully",
+		"recovery_codes": codes,
+	})
+}
+
+// langkah kedua login: tukar challenge token + kode 2FA dengan JWT
+func VerifyTwoFactorLogin(c *gin.Context) {
+	var req model.TwoFactorLoginRequest
+	if !bindTwoFactorRequest(c, &req) {
+		return
+	}
+
+	challengeID, userID, challengeVersion, ok, err := parseTwoFactorChallenge(req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge"})
+		return
+	}
+
+	var email string
+	var secret sql.NullString
+	var tokenVersion int
+	err = config.DB.QueryRow(`
+		SELECT email, totp_secret, token_version FROM users WHERE id = $1 AND totp_enabled_at IS NOT NULL
+	`, userID).Scan(&email, &secret, &tokenVersion)
+	if err == sql.ErrNoRows || (err == nil && tokenVersion != challengeVersion) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+
+	// kode 2FA ikut dihitung di counter brute-force login yang sama
+	email = normalizeEmail(email)
+	ipHash := analytics.VisitorHash(c.ClientIP(), "")
+	now := time.Now()
+
+	wait, err := loginLockedFor(email, ipHash, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if wait > 0 {
+		recordLoginAttempt(c, email, userID, ipHash, false, "locked")
+		tooManyLoginAttempts(c, wait)
+		return
+	}
+
+	method, err := verifySecondFactor(userID, secret.String, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if method == "" {
+		if err := recordLoginFailure(email, ipHash, now); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+			return
+		}
+		_, _ = config.DB.Exec(`UPDATE two_factor_challenges SET attempts = attempts + 1 WHERE id = $1`, challengeID)
+		recordLoginAttempt(c, email, userID, ipHash, false, "invalid_two_factor")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authentication code"})
+		return
+	}
+
+	// challenge sekali pakai, request paralel dengan challenge yang sama hanya satu yang lolos
+	result, err := config.DB.Exec(`
+		UPDATE two_factor_challenges SET used_at = $1 WHERE id = $2 AND used_at IS NULL
+	`, now, challengeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		return
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge"})
+		return
+	}
+
+	clearLoginFailures(email, now)
+	recordLoginAttempt(c, email, userID, ipHash, true, method)
+
+	tokenString, err := generateToken(userID, tokenVersion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	response := gin.H{
+		"message": "Login successfully",
+		"token":   tokenString,
+	}
+	if method == "recovery_code" {
+		response["recovery_codes_remaining"] = remainingRecoveryCodes(userID)
+	}
+	c.JSON(http.StatusOK, response)
+}
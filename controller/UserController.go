@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gibranfajar/backend-codetech/config"
@@ -103,6 +104,11 @@ func CreateUser(c *gin.Context) {
 	password := c.PostForm("password")
 	role := c.PostForm("role")
 
+	if !canAssignRole(c.GetString("role"), role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to assign this role"})
+		return
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
@@ -182,7 +188,7 @@ func UpdateUser(c *gin.Context) {
 
 	// Cek apakah user dengan ID tersebut ada
 	var user model.User
-	err = config.DB.QueryRow("SELECT id FROM users WHERE id = $1", id).Scan(&user.Id)
+	err = config.DB.QueryRow("SELECT id, role FROM users WHERE id = $1", id).Scan(&user.Id, &user.Role)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
@@ -191,6 +197,18 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	// hanya superadmin yang boleh mengubah data superadmin
+	if user.Role == model.RoleSuperadmin && c.GetString("role") != model.RoleSuperadmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to modify this user"})
+		return
+	}
+
+	// perubahan role dicek agar user tidak bisa menaikkan role sendiri / orang lain
+	if role != user.Role && !canAssignRole(c.GetString("role"), role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to assign this role"})
+		return
+	}
+
 	// Cek apakah email sudah digunakan oleh user lain
 	var existingID int
 	err = config.DB.QueryRow("SELECT id FROM users WHERE email = $1 AND id != $2", email, id).Scan(&existingID)
@@ -244,7 +262,8 @@ func UpdateUser(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
 			return
 		}
-		query += `, password = $6 WHERE id = $7`
+		// password diganti admin: semua sesi user tersebut dicabut
+		query += `, password = $6, token_version = token_version + 1 WHERE id = $7`
 		args = append(args, hashedPassword, id)
 	} else {
 		query += ` WHERE id = $6`
@@ -263,6 +282,87 @@ func UpdateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
 }
 
+// update profil user yang sedang login (nama, email, foto); role dan password
+// hanya lewat admin / PUT /users/me/password
+func UpdateCurrentUser(c *gin.Context) {
+	id := c.GetInt("user_id")
+
+	var req model.UserProfileRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	if err := config.Validate.Struct(req); err != nil {
+		var errors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", e.Field(), e.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	email := normalizeEmail(req.Email)
+
+	var currentEmail, currentHash, oldImage string
+	err := config.DB.QueryRow(`SELECT email, password, profile FROM users WHERE id = $1`, id).Scan(&currentEmail, &currentHash, &oldImage)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	// email dipakai untuk login & reset password, jadi ganti email butuh password saat ini
+	if email != normalizeEmail(currentEmail) {
+		if !utils.CheckPasswordHash(req.CurrentPassword, currentHash) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+
+		var existingID int
+		err = config.DB.QueryRow("SELECT id FROM users WHERE LOWER(email) = $1 AND id != $2", email, id).Scan(&existingID)
+		if err == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email already exists"})
+			return
+		} else if err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+	}
+
+	// Upload file baru jika ada
+	profilePath := oldImage
+	if file, err := c.FormFile("profile"); err == nil {
+		os.MkdirAll("uploads", os.ModePerm)
+		filename := uuid.New().String() + filepath.Ext(file.Filename)
+		if err := c.SaveUploadedFile(file, "uploads/"+filename); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+			return
+		}
+		profilePath = "/uploads/" + filename
+
+		// Hapus file lama jika ada
+		if oldImage != "" {
+			_, oldFile := filepath.Split(oldImage)
+			os.Remove("uploads/" + oldFile)
+		}
+	}
+
+	_, err = config.DB.Exec(`
+		UPDATE users SET name = $1, email = $2, profile = $3, updated_at = $4 WHERE id = $5
+	`, name, email, profilePath, time.Now(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
 // delete data
 func DeleteUser(c *gin.Context) {
 	idParam := c.Param("id")
@@ -274,7 +374,7 @@ func DeleteUser(c *gin.Context) {
 
 	// Cek apakah data ada
 	var user model.User
-	err = config.DB.QueryRow("SELECT id FROM users WHERE id = $1", id).Scan(&user.Id)
+	err = config.DB.QueryRow("SELECT id, role FROM users WHERE id = $1", id).Scan(&user.Id, &user.Role)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
@@ -283,6 +383,11 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
+	if user.Role == model.RoleSuperadmin && c.GetString("role") != model.RoleSuperadmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to modify this user"})
+		return
+	}
+
 	// Hapus file lama jika ada
 	var oldImage string
 	err = config.DB.QueryRow("SELECT profile FROM users WHERE id = $1", id).Scan(&oldImage)
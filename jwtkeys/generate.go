@@ -0,0 +1,64 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Generate membuat private key baru dalam format PEM PKCS#8
+func Generate(alg string) ([]byte, error) {
+	var key crypto.Signer
+	var err error
+	switch alg {
+	case AlgEdDSA:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case AlgRS256:
+		key, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// GenerateFile menyimpan kunci baru sebagai <dir>/<kid>.pem, kid kosong = NewKid
+func GenerateFile(dir, alg, kid string) (string, error) {
+	if kid == "" {
+		kid = NewKid(alg)
+	}
+
+	data, err := Generate(alg)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, kid+".pem")
+	// O_EXCL agar kunci yang sudah ada tidak tertimpa
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
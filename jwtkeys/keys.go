@@ -0,0 +1,235 @@
+// Package jwtkeys mengelola kunci penanda tangan JWT (RS256 / EdDSA).
+//
+// Server tidak start tanpa kunci di JWT_KEYS_DIR; buat kunci pertama dengan
+// go run ./cmd/jwtkey lalu bagikan folder yang sama ke semua instance. Untuk
+// development, JWT_KEYS_AUTOGENERATE=true membuat kunci lokal otomatis.
+//
+// Rotasi kunci:
+//  1. buat kunci baru: go run ./cmd/jwtkey -alg EdDSA (file <kid>.pem di JWT_KEYS_DIR)
+//  2. set JWT_SIGNING_KID=<kid lama> lalu restart: kunci baru sudah terbit di JWKS
+//     sehingga service lain sempat memperbarui cache-nya
+//  3. set JWT_SIGNING_KID=<kid baru> (atau kosongkan, kid baru urutannya terakhir)
+//     lalu restart: token baru ditandatangani kunci baru, token lama tetap valid
+//  4. setelah config.JWTTTL lewat, hapus file kunci lama lalu restart
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+type Key struct {
+	Kid     string
+	Alg     string
+	private crypto.Signer
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	if k.Alg == AlgRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+type KeySet struct {
+	keys    map[string]*Key
+	kids    []string
+	signing *Key
+}
+
+var (
+	loadOnce sync.Once
+	current  *KeySet
+	loadErr  error
+)
+
+// Default memuat kunci dari config sekali, dipakai Sign / Parse / JWKS
+func Default() (*KeySet, error) {
+	loadOnce.Do(func() {
+		if config.JWTAutoGenerateKeys {
+			if loadErr = generateDevKey(config.JWTKeysDir); loadErr != nil {
+				return
+			}
+		}
+		current, loadErr = Load(config.JWTKeysDir, config.JWTSigningKid)
+	})
+	return current, loadErr
+}
+
+// ParsePrivateKey membaca kunci PEM (PKCS#8, atau PKCS#1 untuk RSA)
+func ParsePrivateKey(data []byte) (crypto.Signer, string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, "", errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, "", fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, "", errors.New("RSA key must be at least 2048 bits")
+		}
+		return key, AlgRS256, nil
+	case ed25519.PrivateKey:
+		return key, AlgEdDSA, nil
+	}
+	return nil, "", fmt.Errorf("unsupported key type %T", parsed)
+}
+
+// kunci Ed25519 untuk development jika folder belum berisi kunci (JWT_KEYS_AUTOGENERATE=true)
+func generateDevKey(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil || len(files) > 0 {
+		return err
+	}
+
+	path, err := GenerateFile(dir, AlgEdDSA, "")
+	if err != nil {
+		return fmt.Errorf("jwtkeys: failed to generate development key: %w", err)
+	}
+	log.Printf("jwtkeys: no signing keys found, generated development key %s", path)
+	return nil
+}
+
+// Load membaca semua <kid>.pem di dir, error jika folder belum berisi kunci
+func Load(dir, signingKid string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("jwtkeys: no signing keys in %s (create one with go run ./cmd/jwtkey, or set JWT_KEYS_AUTOGENERATE=true for development)", dir)
+	}
+	sort.Strings(files)
+
+	set := &KeySet{keys: map[string]*Key{}}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		private, alg, err := ParsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("jwtkeys: %s: %w", file, err)
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		set.keys[kid] = &Key{Kid: kid, Alg: alg, private: private}
+		set.kids = append(set.kids, kid)
+	}
+
+	if signingKid == "" {
+		signingKid = set.kids[len(set.kids)-1]
+	}
+	set.signing = set.keys[signingKid]
+	if set.signing == nil {
+		return nil, fmt.Errorf("jwtkeys: signing key %q not found in %s", signingKid, dir)
+	}
+	return set, nil
+}
+
+// Sign membuat JWT dengan kunci penanda tangan aktif, header kid diisi
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.method(), claims)
+	token.Header["kid"] = s.signing.Kid
+	return token.SignedString(s.signing.private)
+}
+
+// Parse memverifikasi JWT: kid harus dikenal dan algoritmanya harus cocok dengan kuncinya
+func (s *KeySet) Parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key := s.keys[kid]
+		if key == nil || token.Method.Alg() != key.Alg {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		return key.private.Public(), nil
+	},
+		jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}),
+		jwt.WithIssuer(config.JWTIssuer),
+		jwt.WithExpirationRequired(),
+	)
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// JWKS berisi kunci publik semua kid (RFC 7517) untuk verifikasi oleh service lain
+func (s *KeySet) JWKS() map[string]interface{} {
+	keys := make([]map[string]string, 0, len(s.kids))
+	for _, kid := range s.kids {
+		key := s.keys[kid]
+		jwk := map[string]string{"kid": kid, "use": "sig", "alg": key.Alg}
+		switch pub := key.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk["kty"] = "RSA"
+			jwk["n"] = b64(pub.N.Bytes())
+			jwk["e"] = b64(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk["kty"] = "OKP"
+			jwk["crv"] = "Ed25519"
+			jwk["x"] = b64(pub)
+		}
+		keys = append(keys, jwk)
+	}
+	return map[string]interface{}{"keys": keys}
+}
+
+// Sign memakai KeySet default
+func Sign(claims jwt.Claims) (string, error) {
+	set, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return set.Sign(claims)
+}
+
+// Parse memakai KeySet default
+func Parse(tokenString string) (*jwt.Token, error) {
+	set, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return set.Parse(tokenString)
+}
+
+// NewKid membuat kid berbasis tanggal agar urutan nama = urutan pembuatan
+func NewKid(alg string) string {
+	return time.Now().UTC().Format("20060102-150405") + "-" + strings.ToLower(alg)
+}
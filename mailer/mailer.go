@@ -0,0 +1,131 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+type Message struct {
+	To      string
+	ReplyTo string
+	Subject string
+	Text    string
+	// opsional, jika diisi email dikirim sebagai multipart/alternative
+	HTML string
+}
+
+// Sender adalah tujuan akhir pengiriman email (SMTP, file, log)
+type Sender interface {
+	Send(from string, msg Message) error
+}
+
+var (
+	senderOnce sync.Once
+	sender     Sender
+)
+
+// NewSender membuat Sender sesuai driver (smtp, file, log)
+func NewSender(driver string) (Sender, error) {
+	switch driver {
+	case "smtp":
+		return &SMTPSender{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+		}, nil
+	case "file":
+		return &FileSender{Dir: config.MailDir}, nil
+	case "log":
+		return LogSender{}, nil
+	}
+	return nil, fmt.Errorf("mailer: unknown driver %q", driver)
+}
+
+// SetSender mengganti Sender default, panggil sebelum StartOutbox
+func SetSender(s Sender) {
+	senderOnce.Do(func() {})
+	sender = s
+}
+
+func defaultSender() Sender {
+	senderOnce.Do(func() {
+		s, err := NewSender(config.MailDriver)
+		if err != nil {
+			log.Printf("%s, falling back to log driver", err)
+			s = LogSender{}
+		}
+		sender = s
+	})
+	return sender
+}
+
+// Send mengirim email langsung tanpa outbox, pakai Queue / Enqueue agar ada retry
+func Send(msg Message) error {
+	return defaultSender().Send(config.MailFrom, msg)
+}
+
+// buang CR/LF agar nilai header tidak bisa menyisipkan header lain
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(strings.TrimSpace(v))
+}
+
+func crlf(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
+
+func writePart(w *bytes.Buffer, body string) {
+	qp := quotedprintable.NewWriter(w)
+	qp.Write([]byte(crlf(body)))
+	qp.Close()
+}
+
+// buildMessage menyusun email RFC 5322 lengkap dengan header
+func buildMessage(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", headerValue(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", headerValue(msg.To))
+	if msg.ReplyTo != "" {
+		fmt.Fprintf(&buf, "Reply-To: %s\r\n", headerValue(msg.ReplyTo))
+	}
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(msg.Subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTML == "" {
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		writePart(&buf, msg.Text)
+		return buf.Bytes()
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		w, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		var encoded bytes.Buffer
+		writePart(&encoded, part.content)
+		w.Write(encoded.Bytes())
+	}
+	mw.Close()
+
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	buf.Write(body.Bytes())
+	return buf.Bytes()
+}
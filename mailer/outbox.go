@@ -0,0 +1,150 @@
+package mailer
+
+import (
+	"log"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+var (
+	// interval worker mengecek email yang jatuh tempo
+	OutboxInterval  = 10 * time.Second
+	OutboxBatchSize = 20
+	// setelah percobaan ke-MaxAttempts gagal, email ditandai failed
+	MaxAttempts = 8
+	// baris yang sedang dikirim dikunci selama ini; jika proses mati di tengah
+	// pengiriman, email dicoba lagi setelah lease habis
+	SendLease = 5 * time.Minute
+)
+
+// panjang maksimum kolom mail_outbox.subject (karakter)
+const maxSubjectLength = 255
+
+// Queue menyimpan email ke outbox, pengiriman dilakukan worker
+func Queue(template string, msg Message) (int, error) {
+	// subject dari input user (mis. "[SiteName] " + subject pesan kontak) bisa
+	// melebihi kolom, potong per rune agar email tidak gagal masuk antrean
+	subject := headerValue(msg.Subject)
+	if runes := []rune(subject); len(runes) > maxSubjectLength {
+		subject = string(runes[:maxSubjectLength])
+	}
+
+	var id int
+	err := config.DB.QueryRow(`
+		INSERT INTO mail_outbox (template, to_address, reply_to, subject, text_body, html_body, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $7)
+		RETURNING id
+	`, template, headerValue(msg.To), headerValue(msg.ReplyTo), subject, msg.Text, msg.HTML, time.Now()).Scan(&id)
+	return id, err
+}
+
+// Enqueue render template lalu memasukkannya ke outbox
+func Enqueue(template, to, replyTo string, data Data) (int, error) {
+	msg, err := Render(template, data)
+	if err != nil {
+		return 0, err
+	}
+	msg.To = to
+	msg.ReplyTo = replyTo
+	return Queue(template, msg)
+}
+
+// StartOutbox menjalankan worker pengiriman outbox
+func StartOutbox() {
+	defaultSender()
+	go func() {
+		for {
+			for {
+				n, err := ProcessOutbox()
+				if err != nil {
+					log.Printf("mailer: outbox failed: %s", err)
+				}
+				// batch penuh berarti kemungkinan masih ada antrean, lanjut tanpa jeda
+				if err != nil || n < OutboxBatchSize {
+					break
+				}
+			}
+			time.Sleep(OutboxInterval)
+		}
+	}()
+}
+
+// backoff antar percobaan: 1, 2, 4, 8 ... menit, maksimal 6 jam
+func backoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts && d < 6*time.Hour; i++ {
+		d *= 2
+	}
+	if d > 6*time.Hour {
+		d = 6 * time.Hour
+	}
+	return d
+}
+
+type outboxItem struct {
+	id       int
+	attempts int
+	msg      Message
+}
+
+// ProcessOutbox mengirim satu batch email yang jatuh tempo, return jumlah yang diproses
+func ProcessOutbox() (int, error) {
+	now := time.Now()
+
+	// klaim batch: attempts dinaikkan dan next_attempt_at dimundurkan sebagai lease,
+	// SKIP LOCKED agar aman jika ada beberapa instance
+	rows, err := config.DB.Query(`
+		UPDATE mail_outbox
+		SET attempts = attempts + 1, next_attempt_at = $1, updated_at = $2
+		WHERE id IN (
+			SELECT id FROM mail_outbox
+			WHERE status = 'pending' AND next_attempt_at <= $2
+			ORDER BY next_attempt_at ASC, id ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, attempts, to_address, reply_to, subject, text_body, html_body
+	`, now.Add(SendLease), now, OutboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var items []outboxItem
+	for rows.Next() {
+		var item outboxItem
+		if err := rows.Scan(&item.id, &item.attempts, &item.msg.To, &item.msg.ReplyTo, &item.msg.Subject, &item.msg.Text, &item.msg.HTML); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, item := range items {
+		sendErr := defaultSender().Send(config.MailFrom, item.msg)
+
+		if sendErr == nil {
+			_, err = config.DB.Exec(`
+				UPDATE mail_outbox SET status = 'sent', sent_at = $1, last_error = '', updated_at = $1 WHERE id = $2
+			`, time.Now(), item.id)
+		} else if item.attempts >= MaxAttempts {
+			log.Printf("mailer: giving up on outbox %d after %d attempts: %s", item.id, item.attempts, sendErr)
+			_, err = config.DB.Exec(`
+				UPDATE mail_outbox SET status = 'failed', last_error = $1, updated_at = $2 WHERE id = $3
+			`, sendErr.Error(), time.Now(), item.id)
+		} else {
+			_, err = config.DB.Exec(`
+				UPDATE mail_outbox SET last_error = $1, next_attempt_at = $2, updated_at = $3 WHERE id = $4
+			`, sendErr.Error(), time.Now().Add(backoff(item.attempts)), time.Now(), item.id)
+		}
+		if err != nil {
+			return len(items), err
+		}
+	}
+
+	return len(items), nil
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SMTPSender mengirim lewat server SMTP, tanpa auth jika Username kosong
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+}
+
+func (s *SMTPSender) Send(from string, msg Message) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	return smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, from, []string{msg.To}, buildMessage(from, msg))
+}
+
+// FileSender menyimpan tiap email sebagai file .eml (untuk development)
+type FileSender struct {
+	Dir string
+}
+
+func (s *FileSender) Send(from string, msg Message) error {
+	if err := os.MkdirAll(s.Dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	filename := time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix) + ".eml"
+
+	return os.WriteFile(filepath.Join(s.Dir, filename), buildMessage(from, msg), 0o644)
+}
+
+// LogSender hanya mencetak email ke log (untuk development)
+type LogSender struct{}
+
+func (LogSender) Send(from string, msg Message) error {
+	log.Printf("mailer: from=%s to=%s reply_to=%s subject=%q\n%s", from, msg.To, msg.ReplyTo, msg.Subject, msg.Text)
+	return nil
+}
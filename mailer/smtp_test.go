@@ -0,0 +1,210 @@
+package mailer
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+type smtpEnvelope struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// fakeSMTP adalah server SMTP minimal di localhost yang menerima satu email
+// (pengganti MailHog / smtp4dev untuk test)
+func fakeSMTP(t *testing.T) (host, port string, received <-chan smtpEnvelope) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan smtpEnvelope, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		var env smtpEnvelope
+		tp.PrintfLine("220 localhost fake SMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				tp.PrintfLine("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				env.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				tp.PrintfLine("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				env.to = append(env.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				tp.PrintfLine("250 OK")
+			case cmd == "DATA":
+				tp.PrintfLine("354 end with <CRLF>.<CRLF>")
+				// baca mentah agar CRLF di pesan ikut diperiksa
+				var data bytes.Buffer
+				for {
+					raw, err := tp.R.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if raw == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(raw, "."))
+				}
+				env.data = data.Bytes()
+				tp.PrintfLine("250 OK")
+			case cmd == "QUIT":
+				tp.PrintfLine("221 bye")
+				ch <- env
+				return
+			default:
+				tp.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ = net.SplitHostPort(ln.Addr().String())
+	return host, port, ch
+}
+
+func TestSMTPSenderContactMessage(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+
+	msg, err := Render("contact_message", Data{
+		"Name":    "Budi",
+		"Email":   "budi@example.com",
+		"Subject": "Harga paket ✓\r\nBcc: attacker@example.com",
+		"Message": "Halo,\nsaya mau tanya harga = berapa?\n" + strings.Repeat("panjang ", 20),
+		"SentAt":  "Mon, 02 Jan 2006 15:04:05 +0700",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.To = "cs@codetech.test"
+	msg.ReplyTo = "budi@example.com\r\nX-Injected: 1"
+
+	sender := &SMTPSender{Host: host, Port: port}
+	if err := sender.Send("no-reply@codetech.test", msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	env := <-received
+
+	if env.from != "no-reply@codetech.test" || len(env.to) != 1 || env.to[0] != "cs@codetech.test" {
+		t.Errorf("envelope = %s -> %v", env.from, env.to)
+	}
+
+	// semua baris diakhiri CRLF, tidak ada LF telanjang
+	if bytes.Contains(bytes.ReplaceAll(env.data, []byte("\r\n"), nil), []byte("\n")) {
+		t.Error("message contains bare LF")
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(env.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := parsed.Header
+	for key, want := range map[string]string{
+		"From":         "no-reply@codetech.test",
+		"To":           "cs@codetech.test",
+		"Reply-To":     "budi@example.com  X-Injected: 1",
+		"Mime-Version": "1.0",
+	} {
+		if got := header.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if header.Get("X-Injected") != "" || header.Get("Bcc") != "" {
+		t.Error("CR/LF in header values injected extra headers")
+	}
+	if _, err := header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if err != nil || subject != "["+config.SiteName+"] Harga paket ✓  Bcc: attacker@example.com" {
+		t.Errorf("Subject = %q (%v)", subject, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", header.Get("Content-Type"))
+	}
+	parts := map[string]string{}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if enc := part.Header.Get("Content-Transfer-Encoding"); enc != "quoted-printable" {
+			t.Errorf("part encoding = %q", enc)
+		}
+		raw, _ := io.ReadAll(part)
+		for _, line := range strings.Split(string(raw), "\r\n") {
+			if len(line) > 76 {
+				t.Errorf("quoted-printable line longer than 76: %q", line)
+			}
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(raw)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+
+	text := parts["text/plain"]
+	for _, want := range []string{"Name: Budi\r\n", "Email: budi@example.com\r\n", "saya mau tanya harga = berapa?\r\n", strings.TrimSpace(strings.Repeat("panjang ", 20))} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text part missing %q:\n%s", want, text)
+		}
+	}
+	if html := parts["text/html"]; !strings.Contains(html, "<td>Budi</td>") || !strings.Contains(html, "&#43;0700") {
+		t.Errorf("html part = %q", html)
+	}
+}
+
+func TestBuildMessagePlainText(t *testing.T) {
+	data := buildMessage("no-reply@codetech.test", Message{
+		To:      "a@example.com",
+		Subject: "Halo",
+		Text:    "baris 1\nbaris 2 é\n",
+	})
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.Header.Get("Content-Type"); got != "text/plain; charset=UTF-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if parsed.Header.Get("Reply-To") != "" {
+		t.Error("empty Reply-To written")
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if string(body) != "baris 1\r\nbaris 2 é\r\n" {
+		t.Errorf("body = %q", body)
+	}
+}
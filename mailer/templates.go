@@ -0,0 +1,67 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+// template email ada di folder templates:
+//   - <nama>.txt  wajib, berisi {{define "subject"}} dan isi email teks
+//   - <nama>.html opsional, isi email HTML yang dibungkus layout.html
+//
+//go:embed templates
+var templateFS embed.FS
+
+// Data adalah variabel template, SiteName dan SiteURL selalu tersedia
+type Data map[string]interface{}
+
+// Render membuat Message dari template, To / ReplyTo diisi pemanggil
+func Render(name string, data Data) (Message, error) {
+	vars := Data{"SiteName": config.SiteName, "SiteURL": config.SiteURL}
+	for k, v := range data {
+		vars[k] = v
+	}
+
+	var msg Message
+
+	text, err := texttemplate.ParseFS(templateFS, "templates/"+name+".txt")
+	if err != nil {
+		return msg, err
+	}
+
+	var buf bytes.Buffer
+	if err := text.ExecuteTemplate(&buf, "subject", vars); err != nil {
+		return msg, err
+	}
+	msg.Subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := text.ExecuteTemplate(&buf, name+".txt", vars); err != nil {
+		return msg, err
+	}
+	msg.Text = strings.TrimSpace(buf.String()) + "\n"
+
+	if _, err := fs.Stat(templateFS, "templates/"+name+".html"); err != nil {
+		return msg, nil
+	}
+
+	html, err := htmltemplate.ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+	if err != nil {
+		return msg, err
+	}
+	vars["MailSubject"] = msg.Subject
+
+	buf.Reset()
+	if err := html.ExecuteTemplate(&buf, "layout", vars); err != nil {
+		return msg, err
+	}
+	msg.HTML = buf.String()
+
+	return msg, nil
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"log"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/analytics"
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/controller"
+	"github.com/gibranfajar/backend-codetech/jwtkeys"
+	"github.com/gibranfajar/backend-codetech/mailer"
 	"github.com/gibranfajar/backend-codetech/middlewares"
+	"github.com/gibranfajar/backend-codetech/model"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
@@ -18,13 +23,39 @@ func main() {
 	// validator
 	config.InitValidator()
 
+	// kunci HMAC token email & challenge 2FA wajib diisi
+	if err := config.CheckAppKey(); err != nil {
+		log.Fatal(err)
+	}
+
+	// link absolut feed, sitemap & og:image dibangun dari API_URL
+	if err := config.CheckAPIURL(); err != nil {
+		log.Fatal(err)
+	}
+
+	// kunci penanda tangan JWT
+	if _, err := jwtkeys.Default(); err != nil {
+		log.Fatal(err)
+	}
+
+	// pipeline analytics views artikel (batch write + rollup harian)
+	analytics.StartViewPipeline()
+
+	// worker pengiriman email dari outbox (dengan retry)
+	mailer.StartOutbox()
+
 	// inisialisasi router
 	router := gin.Default()
+	// c.ClientIP() dipakai untuk throttle login & rate limit, jadi X-Forwarded-For
+	// hanya dibaca dari proxy yang terdaftar
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		log.Fatal("invalid TRUSTED_PROXIES: ", err)
+	}
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "https://codetech.crx.my.id"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -36,29 +67,71 @@ func main() {
 		})
 	})
 
+	// feed artikel (RSS, Atom, JSON Feed), opsional ?category=
+	router.GET("/feed.xml", controller.GetRSSFeed)
+	router.GET("/atom.xml", controller.GetAtomFeed)
+	router.GET("/feed.json", controller.GetJSONFeed)
+
+	// kunci publik JWT (JWKS)
+	router.GET("/.well-known/jwks.json", controller.GetJWKS)
+
+	// sitemap untuk mesin pencari
+	router.GET("/sitemap.xml", controller.GetSitemap)
+	router.GET("/sitemap_index.xml", controller.GetSitemapIndex)
+	router.GET("/sitemaps/:file", controller.GetSitemapFile)
+
 	// routers
 	router.POST("/api/login", controller.Login)
-	router.POST("/api/create-user", controller.CreateUser)
+	router.POST("/api/login/2fa", middlewares.RateLimit(10, 5*time.Minute), controller.VerifyTwoFactorLogin)
+	// login SSO (OIDC) untuk staf, aktif jika OIDC_ISSUER diisi
+	router.GET("/api/oidc/login", middlewares.RateLimit(20, 10*time.Minute), controller.OIDCLogin)
+	router.GET("/api/oidc/callback", middlewares.RateLimit(20, 10*time.Minute), controller.OIDCCallback)
+	router.POST("/api/password/forgot", middlewares.RateLimit(3, 15*time.Minute), controller.ForgotPassword)
+	router.POST("/api/password/reset", middlewares.RateLimit(10, 15*time.Minute), controller.ResetPassword)
+	// onboarding user baru lewat undangan dari admin
+	router.GET("/api/invitations/verify", middlewares.RateLimit(20, 15*time.Minute), controller.VerifyInvitation)
+	router.POST("/api/invitations/accept", middlewares.RateLimit(10, 15*time.Minute), controller.AcceptInvitation)
 
 	user := router.Group("/api")
+	// bahasa konten dari ?lang= atau Accept-Language
+	user.Use(middlewares.Locale())
 	user.GET("/pages", controller.GetAllPages)
+	user.GET("/pages/:slug", controller.GetPageBySlug)
 	user.GET("/abouts", controller.GetAllAbout)
 	user.GET("/services", controller.GetAllServices)
+	user.GET("/services/:slug", controller.GetServiceBySlug)
 	user.GET("/portfolios", controller.GetAllPortfolio)
 	user.GET("/products", controller.GetAllProduct)
+	user.GET("/products/compare", controller.CompareProducts)
+	user.POST("/products/:id/inquiries", middlewares.RateLimit(3, 10*time.Minute), controller.CreateInquiry)
 	user.GET("/contacts", controller.GetAllContact)
+	user.POST("/contact-messages", middlewares.RateLimit(3, 10*time.Minute), controller.CreateContactMessage)
 	user.GET("/users", controller.GetUserNotAdmin)
 	user.GET("/category-articles", controller.GetAllCategoryArticle)
+	user.GET("/category-articles/tree", controller.GetCategoryArticleTree)
+	user.GET("/category-articles/:slug/articles", controller.GetCategoryArticles)
 	user.GET("/articles", controller.GetAllArticle)
+	user.GET("/articles/trending", controller.GetTrendingArticles)
+	user.GET("/articles/:slug", controller.GetArticleBySlug)
+	user.GET("/articles/:slug/related", controller.GetRelatedArticles)
+	user.GET("/articles/:slug/comments", controller.GetArticleComments)
+	user.POST("/articles/:slug/comments", middlewares.RateLimit(5, 10*time.Minute), controller.CreateComment)
 	user.GET("/category-faqs", controller.GetAllCategoryFaq)
 	user.GET("/faqs", controller.GetAllFaq)
-	// update counter views artikel
-	user.GET("/articles/:slug/views", controller.IncrementArticleViews)
+	user.GET("/faqs/grouped", controller.GetGroupedFaqs)
+	user.GET("/faqs/search", middlewares.RateLimit(60, time.Minute), controller.SearchFaqs)
+	user.POST("/faqs/:id/votes", middlewares.RateLimit(20, 10*time.Minute), controller.VoteFaq)
+	// catat views artikel (GET dipertahankan untuk frontend lama)
+	user.POST("/articles/:slug/views", controller.RecordArticleView)
+	user.GET("/articles/:slug/views", controller.RecordArticleView)
 
 	// router untuk admin
 	protected := router.Group("/api/admin")
 	protected.Use(middlewares.AuthMiddleware())
 	{
+		// route dashboard statistik
+		protected.GET("/stats", controller.GetDashboardStats)
+
 		// route pages
 		protected.GET("/pages", controller.GetAllPages)
 		protected.POST("/pages", controller.CreatePage)
@@ -88,6 +161,11 @@ func main() {
 		protected.POST("/products", controller.CreateProduct)
 		protected.PUT("/products/:id", controller.UpdateProduct)
 		protected.DELETE("/products/:id", controller.DeleteProduct)
+		protected.GET("/products/:id/features", controller.GetProductFeatures)
+		protected.POST("/products/:id/features", controller.CreateProductFeature)
+		protected.PUT("/products/:id/features/reorder", controller.ReorderProductFeatures)
+		protected.PUT("/products/:id/features/:feature_id", controller.UpdateProductFeature)
+		protected.DELETE("/products/:id/features/:feature_id", controller.DeleteProductFeature)
 
 		// route contacts
 		protected.GET("/contacts", controller.GetAllContact)
@@ -95,23 +173,65 @@ func main() {
 		protected.PUT("/contacts/:id", controller.UpdateContact)
 		protected.DELETE("/contacts/:id", controller.DeleteContact)
 
+		// route inbox pesan kontak
+		protected.GET("/contact-messages", controller.GetAllContactMessages)
+		protected.GET("/contact-messages/:id", controller.GetContactMessage)
+		protected.PUT("/contact-messages/:id/read", controller.MarkContactMessageRead)
+		protected.PUT("/contact-messages/:id/archive", controller.ArchiveContactMessage)
+		protected.DELETE("/contact-messages/:id", controller.DeleteContactMessage)
+
+		userManager := middlewares.RequireRole(model.RoleSuperadmin, model.RoleAdmin)
+
+		// route outbox email (berisi alamat & subject email reset password / undangan)
+		protected.GET("/mail-outbox", userManager, controller.GetMailOutbox)
+		protected.POST("/mail-outbox/:id/retry", userManager, controller.RetryMailOutbox)
+
+		// route undangan user
+		protected.GET("/invitations", userManager, controller.GetAllInvitations)
+		protected.POST("/invitations", userManager, controller.CreateInvitation)
+		protected.DELETE("/invitations/:id", userManager, controller.RevokeInvitation)
+
+		// route 2FA (TOTP) user yang sedang login
+		protected.GET("/2fa", controller.GetTwoFactorStatus)
+		protected.POST("/2fa/setup", controller.SetupTwoFactor)
+		protected.POST("/2fa/enable", controller.EnableTwoFactor)
+		protected.POST("/2fa/disable", middlewares.RateLimit(5, 15*time.Minute), controller.DisableTwoFactor)
+		protected.POST("/2fa/recovery-codes", middlewares.RateLimit(5, 15*time.Minute), controller.RegenerateRecoveryCodes)
+
+		// route API key milik user yang sedang login
+		protected.GET("/api-keys", controller.GetApiKeys)
+		protected.POST("/api-keys", controller.CreateApiKey)
+		protected.DELETE("/api-keys/:id", controller.RevokeApiKey)
+
+		// route keamanan login
+		protected.GET("/login-attempts", userManager, controller.GetLoginAttempts)
+		protected.GET("/login-lockouts", userManager, controller.GetLoginLockouts)
+		protected.DELETE("/login-lockouts/:scope/:key", userManager, controller.UnlockLogin)
+
 		// route users
 		protected.GET("/users", controller.GetAllUser)
-		protected.POST("/users", controller.CreateUser)
-		protected.PUT("/users/:id", controller.UpdateUser)
-		protected.DELETE("/users/:id", controller.DeleteUser)
+		protected.POST("/users", userManager, controller.CreateUser)
+		protected.PUT("/users/:id", userManager, controller.UpdateUser)
+		protected.DELETE("/users/:id", userManager, controller.DeleteUser)
 		// get user by is login
 		protected.GET("/users/me", controller.GetUser)
+		protected.PUT("/users/me", controller.UpdateCurrentUser)
+		protected.PUT("/users/me/password", middlewares.RateLimit(5, 15*time.Minute), controller.ChangePassword)
 
 		// route category faq
 		protected.GET("/category-faqs", controller.GetAllCategoryFaq)
 		protected.POST("/category-faqs", controller.CreateCategoryFaq)
+		protected.PUT("/category-faqs/reorder", controller.ReorderCategoryFaqs)
 		protected.PUT("/category-faqs/:id", controller.UpdateCategoryFaq)
 		protected.DELETE("/category-faqs/:id", controller.DeleteCategoryFaq)
 
 		// route faq
 		protected.GET("/faqs", controller.GetAllFaq)
 		protected.POST("/faqs", controller.CreateFaq)
+		protected.GET("/faqs/report", controller.GetFaqHelpfulnessReport)
+		protected.DELETE("/faqs/search-misses", controller.DeleteFaqSearchMisses)
+		protected.GET("/faqs/:id/feedback", controller.GetFaqFeedback)
+		protected.PUT("/faqs/reorder", controller.ReorderFaqs)
 		protected.PUT("/faqs/:id", controller.UpdateFaq)
 		protected.DELETE("/faqs/:id", controller.DeleteFaq)
 
@@ -126,6 +246,23 @@ func main() {
 		protected.POST("/articles", controller.CreateArticle)
 		protected.PUT("/articles/:id", controller.UpdateArticle)
 		protected.DELETE("/articles/:id", controller.DeleteArticle)
+
+		// route terjemahan konten (pages, abouts, services, products, faqs, articles)
+		protected.GET("/translations/:resource/:id", controller.GetTranslations)
+		protected.PUT("/translations/:resource/:id/:locale", controller.UpsertTranslation)
+		protected.DELETE("/translations/:resource/:id/:locale", controller.DeleteTranslation)
+
+		// route inquiry produk
+		protected.GET("/inquiries", controller.GetAllInquiries)
+		protected.GET("/inquiries/:id", controller.GetInquiry)
+		protected.PUT("/inquiries/:id/status", controller.UpdateInquiryStatus)
+		protected.PUT("/inquiries/:id/assign", controller.AssignInquiry)
+		protected.POST("/inquiries/:id/notes", controller.CreateInquiryNote)
+
+		// route moderasi komentar
+		protected.GET("/comments", controller.GetAllComments)
+		protected.PUT("/comments/:id/status", controller.UpdateCommentStatus)
+		protected.DELETE("/comments/:id", controller.DeleteComment)
 	}
 
 	// route static untuk menampilkan gambar
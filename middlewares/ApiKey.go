@@ -0,0 +1,113 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/analytics"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// resource yang hanya bisa diakses dengan login manusia (JWT), bukan API key
+var apiKeyBlockedResources = map[string]bool{
+	"api-keys": true,
+	"2fa":      true,
+}
+
+// scope yang dibutuhkan route: <segmen pertama setelah /api/admin>:<read|write>,
+// GET / HEAD butuh read, method lain butuh write
+func routeScope(c *gin.Context) (string, string) {
+	path := strings.TrimPrefix(c.FullPath(), "/api/admin/")
+	resource, _, _ := strings.Cut(path, "/")
+
+	action := "write"
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		action = "read"
+	}
+	return resource, action
+}
+
+// scope "*" berlaku untuk semua resource, write sekaligus memberi read
+func scopeAllows(scopes []string, resource, action string) bool {
+	for _, scope := range scopes {
+		r, a, _ := strings.Cut(scope, ":")
+		if (r == resource || r == "*") && (a == action || a == "write") {
+			return true
+		}
+	}
+	return false
+}
+
+// autentikasi lewat header X-API-Key, user_id + role diisi dari pemilik key
+func authenticateAPIKey(c *gin.Context, key string) {
+	invalid := func() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		c.Abort()
+	}
+
+	prefix, ok := utils.ParseAPIKey(key)
+	if !ok {
+		invalid()
+		return
+	}
+
+	// key dari sebelum password pemiliknya diganti / direset (token_version naik) ikut dicabut
+	var keyID, userID int
+	var keyHash, role string
+	var scopes []string
+	var expiresAt sql.NullTime
+	var twoFactorEnabled bool
+	err := config.DB.QueryRow(`
+		SELECT k.id, k.user_id, k.key_hash, k.scopes, k.expires_at, u.role, u.totp_enabled_at IS NOT NULL
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.prefix = $1 AND k.revoked_at IS NULL AND k.token_version = u.token_version
+	`, prefix).Scan(&keyID, &userID, &keyHash, pq.Array(&scopes), &expiresAt, &role, &twoFactorEnabled)
+	if err != nil || subtle.ConstantTimeCompare([]byte(keyHash), []byte(utils.HashToken(strings.TrimSpace(key)))) != 1 {
+		invalid()
+		return
+	}
+
+	now := time.Now()
+	if expiresAt.Valid && !expiresAt.Time.After(now) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key expired"})
+		c.Abort()
+		return
+	}
+
+	// kebijakan 2FA wajib sama dengan login JWT: pemilik harus sudah enroll TOTP
+	if config.TwoFactorRequired(role) && !twoFactorEnabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication setup required"})
+		c.Abort()
+		return
+	}
+
+	resource, action := routeScope(c)
+	if apiKeyBlockedResources[resource] || c.FullPath() == "/api/admin/users/me/password" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This endpoint requires a user login"})
+		c.Abort()
+		return
+	}
+	if !scopeAllows(scopes, resource, action) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key is missing scope " + resource + ":" + action})
+		c.Abort()
+		return
+	}
+
+	// last_used_at cukup diperbarui paling sering sekali per menit
+	_, _ = config.DB.Exec(`
+		UPDATE api_keys SET last_used_at = $1, last_used_ip_hash = $2
+		WHERE id = $3 AND (last_used_at IS NULL OR last_used_at < $4)
+	`, now, analytics.VisitorHash(c.ClientIP(), ""), keyID, now.Add(-time.Minute))
+
+	c.Set("user_id", userID)
+	c.Set("role", role)
+	c.Set("api_key_id", keyID)
+	c.Next()
+}
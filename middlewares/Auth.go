@@ -4,14 +4,29 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/jwtkeys"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("secret-codetech")
+// route yang tetap terbuka untuk admin yang belum mengaktifkan 2FA wajib
+var twoFactorSetupRoutes = map[string]bool{
+	"/api/admin/2fa":               true,
+	"/api/admin/2fa/setup":         true,
+	"/api/admin/2fa/enable":        true,
+	"/api/admin/users/me":          true,
+	"/api/admin/users/me/password": true,
+}
 
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// client mesin memakai X-API-Key, user memakai Bearer JWT
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateAPIKey(c, apiKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -21,13 +36,8 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// validasi metode signing
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return jwtSecret, nil
-		})
+		// verifikasi RS256 / EdDSA dengan kunci sesuai header kid
+		token, err := jwtkeys.Parse(tokenString)
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
@@ -42,8 +52,67 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid claims"})
+			c.Abort()
+			return
+		}
+
+		// token dicabut jika versinya sudah tidak sama (ganti / reset password),
+		// token lama tanpa claim "ver" dianggap versi 0
+		version, _ := claims["ver"].(float64)
+		var tokenVersion int
+		var role string
+		var twoFactorEnabled bool
+		err = config.DB.QueryRow(`
+			SELECT token_version, role, totp_enabled_at IS NOT NULL FROM users WHERE id = $1
+		`, int(userID)).Scan(&tokenVersion, &role, &twoFactorEnabled)
+		if err != nil || tokenVersion != int(version) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token revoked"})
+			c.Abort()
+			return
+		}
+
+		// role wajib 2FA yang belum enroll hanya boleh membuka route setup 2FA,
+		// kecuali login SSO dengan MFA identity provider yang dipercaya
+		ssoMFA := config.OIDCSatisfiesTwoFactor && hasAMR(claims, "mfa")
+		if config.TwoFactorRequired(role) && !twoFactorEnabled && !ssoMFA && !twoFactorSetupRoutes[c.FullPath()] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication setup required"})
+			c.Abort()
+			return
+		}
+
 		// Simpan user_id di context
-		c.Set("user_id", int(claims["user_id"].(float64)))
+		c.Set("user_id", int(userID))
+		c.Set("role", role)
 		c.Next()
 	}
 }
+
+// metode autentikasi login (claim "amr") yang tercatat di token
+func hasAMR(claims jwt.MapClaims, method string) bool {
+	amr, _ := claims["amr"].([]interface{})
+	for _, m := range amr {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole membatasi route untuk role tertentu, dipasang setelah AuthMiddleware
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, r := range roles {
+			if r == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		c.Abort()
+	}
+}
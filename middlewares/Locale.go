@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gin-gonic/gin"
+)
+
+// Locale memilih bahasa konten dari ?lang= atau header Accept-Language,
+// fallback ke config.DefaultLocale. Hasilnya disimpan di context "locale".
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := config.DefaultLocale
+
+		if lang := strings.ToLower(c.Query("lang")); lang != "" && config.IsSupportedLocale(lang) {
+			locale = lang
+		} else if accepted := parseAcceptLanguage(c.GetHeader("Accept-Language")); accepted != "" {
+			locale = accepted
+		}
+
+		c.Set("locale", locale)
+		c.Header("Content-Language", locale)
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// ambil locale yang didukung dengan nilai q tertinggi, contoh "en-US,en;q=0.9,id;q=0.8"
+func parseAcceptLanguage(header string) string {
+	type langQ struct {
+		lang string
+		q    float64
+	}
+
+	var langs []langQ
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		q := 1.0
+		if idx := strings.Index(part, ";"); idx != -1 {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(part[idx+1:]), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+			part = part[:idx]
+		}
+
+		// cukup subtag utama: en-US -> en
+		primary := strings.ToLower(strings.SplitN(strings.TrimSpace(part), "-", 2)[0])
+		langs = append(langs, langQ{lang: primary, q: q})
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	for _, l := range langs {
+		if l.q > 0 && config.IsSupportedLocale(l.lang) {
+			return l.lang
+		}
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimit membatasi jumlah request per IP per route dalam satu jendela waktu.
+// IP diambil dari c.ClientIP(), yang hanya membaca X-Forwarded-For dari proxy di
+// config.TrustedProxies (router.SetTrustedProxies di main) sehingga tidak bisa dipalsukan client
+func RateLimit(max int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	windows := make(map[string]*rateWindow)
+	lastPrune := time.Now()
+
+	return func(c *gin.Context) {
+		key := c.ClientIP() + "|" + c.FullPath()
+		now := time.Now()
+
+		mu.Lock()
+		// bersihkan jendela yang sudah kedaluwarsa
+		if now.Sub(lastPrune) > window {
+			for k, w := range windows {
+				if now.After(w.resetAt) {
+					delete(windows, k)
+				}
+			}
+			lastPrune = now
+		}
+
+		w, ok := windows[key]
+		if !ok || now.After(w.resetAt) {
+			w = &rateWindow{resetAt: now.Add(window)}
+			windows[key] = w
+		}
+		w.count++
+		exceeded := w.count > max
+		retryAfter := int(w.resetAt.Sub(now).Seconds()) + 1
+		mu.Unlock()
+
+		if exceeded {
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please try again later"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
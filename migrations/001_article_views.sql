@@ -0,0 +1,18 @@
+-- event view artikel (raw), satu baris per kunjungan unik dalam jendela dedup
+CREATE TABLE IF NOT EXISTS article_views (
+    id BIGSERIAL PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    visitor_hash VARCHAR(64) NOT NULL,
+    viewed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_views_article_viewed ON article_views (article_id, viewed_at);
+CREATE INDEX IF NOT EXISTS idx_article_views_viewed ON article_views (viewed_at);
+
+-- rollup harian, sumber penambahan kolom articles.views
+CREATE TABLE IF NOT EXISTS article_view_daily (
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, day)
+);
@@ -0,0 +1,2 @@
+-- format sumber deskripsi artikel: html (legacy) atau markdown
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS content_format VARCHAR(16) NOT NULL DEFAULT 'html';
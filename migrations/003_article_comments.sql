@@ -0,0 +1,18 @@
+-- komentar artikel dengan antrian moderasi
+CREATE TABLE IF NOT EXISTS article_comments (
+    id SERIAL PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    parent_id INTEGER REFERENCES article_comments(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending | approved | rejected | spam
+    spam_score INTEGER NOT NULL DEFAULT 0,
+    ip_hash VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_comments_article_status ON article_comments (article_id, status);
+CREATE INDEX IF NOT EXISTS idx_article_comments_status_created ON article_comments (status, created_at);
@@ -0,0 +1,21 @@
+-- metadata SEO untuk pages, articles dan services
+ALTER TABLE pages
+    ADD COLUMN IF NOT EXISTS meta_title VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS canonical_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS noindex BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS meta_title VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS canonical_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS noindex BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS meta_title VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS canonical_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS noindex BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,14 @@
+-- terjemahan konten per locale, kolom asli tabel dianggap locale default (id)
+CREATE TABLE IF NOT EXISTS translations (
+    id SERIAL PRIMARY KEY,
+    resource VARCHAR(32) NOT NULL,   -- pages | abouts | services | products | faqs | articles
+    resource_id INTEGER NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (resource, resource_id, locale, field)
+);
+
+CREATE INDEX IF NOT EXISTS idx_translations_lookup ON translations (resource, locale, resource_id);
@@ -0,0 +1,45 @@
+-- urutan tampil faq & kategori faq
+BEGIN;
+
+ALTER TABLE category_faqs ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE faqs ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+-- category_id sebelumnya disimpan sebagai teks, nilai kosong / bukan angka menjadi NULL
+ALTER TABLE faqs ALTER COLUMN category_id DROP NOT NULL;
+ALTER TABLE faqs ALTER COLUMN category_id TYPE INTEGER
+    USING CASE WHEN TRIM(category_id::text) ~ '^[0-9]{1,9}$' THEN TRIM(category_id::text)::integer END;
+
+-- faq tanpa kategori valid (NULL / kategori sudah dihapus) dipindah ke kategori "Tanpa Kategori"
+INSERT INTO category_faqs (category, description, icon, created_at, updated_at)
+SELECT 'Tanpa Kategori', 'Faq yang kategorinya sudah tidak ada', '', NOW(), NOW()
+WHERE EXISTS (
+    SELECT 1 FROM faqs f
+    WHERE f.category_id IS NULL OR NOT EXISTS (SELECT 1 FROM category_faqs c WHERE c.id = f.category_id)
+) AND NOT EXISTS (SELECT 1 FROM category_faqs WHERE category = 'Tanpa Kategori');
+
+UPDATE faqs f SET category_id = (SELECT id FROM category_faqs WHERE category = 'Tanpa Kategori' ORDER BY id LIMIT 1)
+WHERE f.category_id IS NULL OR NOT EXISTS (SELECT 1 FROM category_faqs c WHERE c.id = f.category_id);
+
+ALTER TABLE faqs ALTER COLUMN category_id SET NOT NULL;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'faqs_category_id_fkey') THEN
+        ALTER TABLE faqs
+            ADD CONSTRAINT faqs_category_id_fkey
+            FOREIGN KEY (category_id) REFERENCES category_faqs (id) ON DELETE RESTRICT;
+    END IF;
+END $$;
+
+-- isi posisi awal sesuai urutan dibuat
+UPDATE category_faqs c SET position = o.rn
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS rn FROM category_faqs) o
+WHERE c.id = o.id AND c.position = 0;
+
+UPDATE faqs f SET position = o.rn
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY created_at, id) AS rn FROM faqs) o
+WHERE f.id = o.id AND f.position = 0;
+
+CREATE INDEX IF NOT EXISTS idx_faqs_category_position ON faqs (category_id, position);
+
+COMMIT;
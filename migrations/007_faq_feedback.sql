@@ -0,0 +1,22 @@
+-- vote "apakah jawaban ini membantu?" per faq, satu vote per pengunjung
+CREATE TABLE IF NOT EXISTS faq_votes (
+    id SERIAL PRIMARY KEY,
+    faq_id INTEGER NOT NULL REFERENCES faqs (id) ON DELETE CASCADE,
+    helpful BOOLEAN NOT NULL,
+    feedback TEXT NOT NULL DEFAULT '',
+    visitor_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (faq_id, visitor_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_faq_votes_faq ON faq_votes (faq_id, helpful);
+
+-- kata kunci pencarian faq yang tidak menemukan hasil
+CREATE TABLE IF NOT EXISTS faq_search_misses (
+    id SERIAL PRIMARY KEY,
+    query VARCHAR(255) NOT NULL UNIQUE,
+    count INTEGER NOT NULL DEFAULT 1,
+    first_searched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_searched_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,27 @@
+-- artikel wajib punya kategori yang valid, hapus kategori dicek di aplikasi (reassign / 409)
+BEGIN;
+
+-- artikel yang kategorinya sudah dihapus dipindah ke kategori "Tanpa Kategori"
+INSERT INTO category_articles (category, created_at, updated_at)
+SELECT 'Tanpa Kategori', NOW(), NOW()
+WHERE EXISTS (
+    SELECT 1 FROM articles a
+    WHERE a.category_id IS NULL OR NOT EXISTS (SELECT 1 FROM category_articles c WHERE c.id = a.category_id)
+) AND NOT EXISTS (SELECT 1 FROM category_articles WHERE category = 'Tanpa Kategori');
+
+UPDATE articles a SET category_id = (SELECT id FROM category_articles WHERE category = 'Tanpa Kategori' ORDER BY id LIMIT 1)
+WHERE a.category_id IS NULL OR NOT EXISTS (SELECT 1 FROM category_articles c WHERE c.id = a.category_id);
+
+-- NOT VALID dulu agar penambahan constraint tidak mengunci tabel untuk scan penuh
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'articles_category_id_fkey') THEN
+        ALTER TABLE articles
+            ADD CONSTRAINT articles_category_id_fkey
+            FOREIGN KEY (category_id) REFERENCES category_articles (id) ON DELETE RESTRICT NOT VALID;
+    END IF;
+END $$;
+
+ALTER TABLE articles VALIDATE CONSTRAINT articles_category_id_fkey;
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- kategori artikel bertingkat + slug + deskripsi
+ALTER TABLE category_articles
+    ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES category_articles (id) ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS slug VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+
+-- slug awal dari nama kategori, nama kembar diberi akhiran id
+UPDATE category_articles
+SET slug = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(category), '[^a-z0-9]+', '-', 'g'))
+WHERE slug IS NULL;
+
+UPDATE category_articles c
+SET slug = c.slug || '-' || c.id
+WHERE EXISTS (SELECT 1 FROM category_articles o WHERE o.slug = c.slug AND o.id < c.id);
+
+ALTER TABLE category_articles ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_category_articles_slug ON category_articles (slug);
+CREATE INDEX IF NOT EXISTS idx_category_articles_parent ON category_articles (parent_id);
@@ -0,0 +1,25 @@
+-- harga produk desimal + mata uang + jenis diskon dengan masa berlaku
+ALTER TABLE products
+    ALTER COLUMN price TYPE NUMERIC(14,2) USING price::numeric,
+    ALTER COLUMN discount TYPE NUMERIC(14,2) USING COALESCE(discount, 0)::numeric,
+    ALTER COLUMN discount SET DEFAULT 0,
+    ALTER COLUMN discount SET NOT NULL,
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    ADD COLUMN IF NOT EXISTS discount_type VARCHAR(10) NOT NULL DEFAULT 'fixed',
+    ADD COLUMN IF NOT EXISTS discount_starts_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS discount_ends_at TIMESTAMP;
+
+-- diskon lama dianggap potongan nominal, tidak boleh melebihi harga
+UPDATE products SET discount = LEAST(GREATEST(discount, 0), price);
+
+ALTER TABLE products
+    ADD CONSTRAINT products_price_check CHECK (price >= 0),
+    ADD CONSTRAINT products_discount_type_check CHECK (discount_type IN ('percent', 'fixed')),
+    ADD CONSTRAINT products_discount_check CHECK (
+        discount >= 0
+        AND (discount_type <> 'percent' OR discount <= 100)
+        AND (discount_type <> 'fixed' OR discount <= price)
+    ),
+    ADD CONSTRAINT products_discount_window_check CHECK (
+        discount_starts_at IS NULL OR discount_ends_at IS NULL OR discount_ends_at > discount_starts_at
+    );
@@ -0,0 +1,15 @@
+-- fitur terstruktur per produk untuk tabel harga
+ALTER TABLE products ADD COLUMN IF NOT EXISTS highlighted BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS product_features (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    label VARCHAR(255) NOT NULL,
+    value VARCHAR(255) NOT NULL DEFAULT '',
+    included BOOLEAN NOT NULL DEFAULT TRUE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_features_product ON product_features (product_id, position);
@@ -0,0 +1,31 @@
+-- permintaan / order dari pengunjung untuk produk
+CREATE TABLE IF NOT EXISTS inquiries (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER REFERENCES products (id) ON DELETE SET NULL,
+    product_title VARCHAR(255) NOT NULL DEFAULT '', -- salinan judul saat inquiry dibuat
+    plan VARCHAR(100) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(30) NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'contacted', 'won', 'lost')),
+    assigned_to INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    ip_hash VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inquiries_status ON inquiries (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_inquiries_assigned ON inquiries (assigned_to);
+
+-- catatan tindak lanjut admin
+CREATE TABLE IF NOT EXISTS inquiry_notes (
+    id SERIAL PRIMARY KEY,
+    inquiry_id INTEGER NOT NULL REFERENCES inquiries (id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    note TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inquiry_notes_inquiry ON inquiry_notes (inquiry_id, created_at);
@@ -0,0 +1,18 @@
+-- pesan dari form kontak publik
+CREATE TABLE IF NOT EXISTS contact_messages (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(30) NOT NULL DEFAULT '',
+    subject VARCHAR(255) NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    ip_hash VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMP,
+    archived_at TIMESTAMP,
+    notified_at TIMESTAMP, -- email notifikasi terkirim
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_messages_inbox ON contact_messages (archived_at, created_at DESC);
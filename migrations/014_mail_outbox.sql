@@ -0,0 +1,20 @@
+-- antrean email keluar, dikirim worker mailer dengan retry
+CREATE TABLE IF NOT EXISTS mail_outbox (
+    id SERIAL PRIMARY KEY,
+    template VARCHAR(100) NOT NULL DEFAULT '',
+    to_address VARCHAR(255) NOT NULL,
+    reply_to VARCHAR(255) NOT NULL DEFAULT '',
+    subject VARCHAR(255) NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mail_outbox_due ON mail_outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_mail_outbox_status ON mail_outbox (status, created_at DESC);
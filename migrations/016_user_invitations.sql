@@ -0,0 +1,18 @@
+-- undangan user baru oleh admin (pengganti registrasi publik)
+CREATE TABLE IF NOT EXISTS user_invitations (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    invited_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    accepted_user_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- satu undangan aktif per email
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_invitations_open_email
+    ON user_invitations (LOWER(email)) WHERE accepted_at IS NULL AND revoked_at IS NULL;
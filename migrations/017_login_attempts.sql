@@ -0,0 +1,24 @@
+-- audit semua percobaan login
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    user_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    ip_hash VARCHAR(64) NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(50) NOT NULL DEFAULT '', -- invalid_credentials / locked
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_email ON login_attempts (email, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip ON login_attempts (ip_hash, created_at DESC);
+
+-- counter gagal login per akun (email) dan per IP (hash) + waktu kunci
+CREATE TABLE IF NOT EXISTS login_throttles (
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('account', 'ip')),
+    key VARCHAR(255) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    PRIMARY KEY (scope, key)
+);
@@ -0,0 +1,16 @@
+-- API key untuk client mesin (build static site, script), bertindak atas nama user
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL UNIQUE,
+    key_hash VARCHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}', -- contoh: articles:read, faqs:write, *:read
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    last_used_ip_hash VARCHAR(64) NOT NULL DEFAULT '',
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id);
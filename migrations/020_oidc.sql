@@ -0,0 +1,13 @@
+-- identitas SSO (claim sub) yang terhubung ke user, diisi saat login SSO pertama
+ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_subject VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc_subject ON users (oidc_subject) WHERE oidc_subject IS NOT NULL;
+
+-- state login SSO yang sedang berjalan (PKCE verifier + nonce), sekali pakai
+CREATE TABLE IF NOT EXISTS oidc_states (
+    id SERIAL PRIMARY KEY,
+    state_hash VARCHAR(64) NOT NULL UNIQUE,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,14 @@
+-- challenge langkah kedua login (setelah password / SSO), token hanya berisi
+-- id baris + rahasia acak yang disimpan sebagai hash, sekali pakai
+CREATE TABLE IF NOT EXISTS two_factor_challenges (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    token_version INTEGER NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_challenges_user ON two_factor_challenges (user_id);
@@ -0,0 +1,5 @@
+-- API key ikut dicabut saat password pemiliknya diganti / direset: key hanya berlaku
+-- selama token_version-nya sama dengan users.token_version
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+
+UPDATE api_keys k SET token_version = u.token_version FROM users u WHERE u.id = k.user_id;
@@ -0,0 +1,4 @@
+-- status notifikasi pesan kontak diambil dari outbox: notified_at = waktu email
+-- benar-benar terkirim (mail_outbox.sent_at), bukan waktu masuk antrean
+ALTER TABLE contact_messages ADD COLUMN IF NOT EXISTS notification_id INTEGER REFERENCES mail_outbox (id) ON DELETE SET NULL;
+ALTER TABLE contact_messages DROP COLUMN IF EXISTS notified_at;
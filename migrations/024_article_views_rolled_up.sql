@@ -0,0 +1,9 @@
+-- event mentah yang sudah masuk article_view_daily ditandai, sehingga event yang
+-- telat masuk (flush batch sekitar tengah malam) tetap ikut di-rollup berikutnya
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS rolled_up BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE article_views v SET rolled_up = TRUE
+FROM article_view_daily d
+WHERE d.article_id = v.article_id AND d.day = v.viewed_at::date;
+
+CREATE INDEX IF NOT EXISTS idx_article_views_pending_rollup ON article_views (viewed_at) WHERE rolled_up = FALSE;
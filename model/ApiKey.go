@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type ApiKey struct {
+	Id         int        `json:"id"`
+	UserId     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// scopes boleh dikirim berulang (scopes=a&scopes=b) atau dipisah koma
+type ApiKeyRequest struct {
+	Name          string   `form:"name" validate:"required,max=100"`
+	Scopes        []string `form:"scopes" validate:"required,min=1,dive,required,max=100"`
+	ExpiresInDays int      `form:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
@@ -3,34 +3,48 @@ package model
 import "time"
 
 type Article struct {
-	Id          int       `json:"id"`
-	Title       string    `json:"title"`
-	Slug        string    `json:"slug"`
-	UserId      int       `json:"user_id"`
-	CategoryId  int       `json:"category_id"`
-	Description string    `json:"description"`
-	Thumbnail   string    `json:"thumbnail"`
-	Views       int       `json:"views"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id            int       `json:"id"`
+	Title         string    `json:"title"`
+	Slug          string    `json:"slug"`
+	UserId        int       `json:"user_id"`
+	CategoryId    int       `json:"category_id"`
+	Description   string    `json:"description"`
+	ContentFormat string    `json:"content_format"`
+	Thumbnail     string    `json:"thumbnail"`
+	Views         int       `json:"views"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type ResponseArticle struct {
-	Id          int       `json:"id"`
-	Title       string    `json:"title"`
-	Slug        string    `json:"slug"`
-	User        string    `json:"user"`
-	Category    string    `json:"category"`
-	Description string    `json:"description"`
-	Thumbnail   string    `json:"thumbnail"`
-	Views       int       `json:"views"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id            int        `json:"id"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	User          string     `json:"user"`
+	Category      string     `json:"category"`
+	Description   string     `json:"description"`
+	ContentFormat string     `json:"content_format"`
+	Excerpt       string     `json:"excerpt"`
+	ReadingTime   int        `json:"reading_time"`
+	Thumbnail     string     `json:"thumbnail"`
+	Views         int        `json:"views"`
+	CommentCount  int        `json:"comment_count"`
+	Seo           *SeoFields `json:"seo,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 type ArticleRequest struct {
-	Title       string `form:"title" validate:"required"`
-	Description string `form:"description" validate:"required"`
-	CategoryId  int    `form:"category_id" validate:"required"` // Add CategoryId field for article creation
-	UserId      int    `form:"user_id" validate:"required"`     // Add UserId field for article creation
+	Title         string `form:"title" validate:"required"`
+	Description   string `form:"description" validate:"required"`
+	ContentFormat string `form:"content_format" validate:"omitempty,oneof=html markdown"` // default html
+	CategoryId    int    `form:"category_id" validate:"required"`                         // Add CategoryId field for article creation
+	UserId        int    `form:"user_id" validate:"required"`                             // Add UserId field for article creation
+	SeoRequest
+}
+
+type TrendingArticle struct {
+	ResponseArticle
+	RecentViews int     `json:"recent_views"`
+	Score       float64 `json:"score"`
 }
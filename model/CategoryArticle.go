@@ -3,12 +3,26 @@ package model
 import "time"
 
 type CategoryArticle struct {
-	Id        int       `json:"id"`
-	Category  string    `json:"category"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id          int       `json:"id"`
+	ParentId    *int      `json:"parent_id"`
+	Category    string    `json:"category"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 type CategoryArticleRequest struct {
-	Category string `form:"category" validate:"required"`
+	Category    string `form:"category" validate:"required"`
+	Slug        string `form:"slug" validate:"omitempty,max=255"` // default dari nama kategori
+	Description string `form:"description"`
+	ParentId    int    `form:"parent_id" validate:"omitempty,min=1"` // kosong = kategori utama
+}
+
+// node pohon kategori, total_articles termasuk sub kategori
+type CategoryArticleTree struct {
+	CategoryArticle
+	ArticleCount  int                   `json:"article_count"`
+	TotalArticles int                   `json:"total_articles"`
+	Children      []CategoryArticleTree `json:"children"`
 }
@@ -7,6 +7,7 @@ type CategoryFaq struct {
 	Category    string    `json:"category"`
 	Description string    `json:"description"`
 	Icon        string    `json:"icon"`
+	Position    int       `json:"position"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+type Comment struct {
+	Id        int       `json:"id"`
+	ArticleId int       `json:"article_id"`
+	ParentId  *int      `json:"parent_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Body      string    `json:"body"`
+	Status    string    `json:"status"`
+	SpamScore int       `json:"spam_score"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CommentRequest struct {
+	Name     string `form:"name" validate:"required,min=2,max=100"`
+	Email    string `form:"email" validate:"required,email,max=255"`
+	Body     string `form:"body" validate:"required,min=3,max=5000"`
+	ParentId int    `form:"parent_id" validate:"omitempty,min=1"`
+}
+
+type CommentStatusRequest struct {
+	Status string `form:"status" validate:"required,oneof=approved rejected spam pending"`
+}
+
+// komentar publik (tanpa email) dengan balasan bertingkat
+type CommentResponse struct {
+	Id        int               `json:"id"`
+	ParentId  *int              `json:"parent_id"`
+	Name      string            `json:"name"`
+	Body      string            `json:"body"`
+	CreatedAt time.Time         `json:"created_at"`
+	Replies   []CommentResponse `json:"replies"`
+}
+
+// komentar di antrian moderasi admin
+type CommentModeration struct {
+	Comment
+	ArticleTitle string `json:"article_title"`
+	ArticleSlug  string `json:"article_slug"`
+}
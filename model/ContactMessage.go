@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type ContactMessage struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Phone      string     `json:"phone"`
+	Subject    string     `json:"subject"`
+	Message    string     `json:"message"`
+	Read       bool       `json:"read"`
+	Archived   bool       `json:"archived"`
+	ReadAt     *time.Time `json:"read_at"`
+	ArchivedAt *time.Time `json:"archived_at"`
+	NotifiedAt *time.Time `json:"notified_at"`
+	// status email notifikasi di outbox: pending / sent / failed, kosong jika tidak ada
+	NotificationStatus string    `json:"notification_status"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type ContactMessageRequest struct {
+	Name    string `form:"name" validate:"required,min=2,max=100"`
+	Email   string `form:"email" validate:"required,email,max=255"`
+	Phone   string `form:"phone" validate:"omitempty,min=6,max=30"`
+	Subject string `form:"subject" validate:"max=255"`
+	Message string `form:"message" validate:"required,min=10,max=5000"`
+}
+
+// read=1 / archived=1 untuk menandai, 0 untuk membatalkan
+type ContactMessageFlagRequest struct {
+	Value *bool `form:"value" validate:"required"`
+}
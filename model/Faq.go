@@ -6,7 +6,8 @@ type Faq struct {
 	Id         int       `json:"id"`
 	Question   string    `json:"question"`
 	Answer     string    `json:"answer"`
-	CategoryId string    `json:"category_id"`
+	CategoryId int       `json:"category_id"`
+	Position   int       `json:"position"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -14,14 +15,28 @@ type Faq struct {
 type FaqRequest struct {
 	Question   string `form:"question" validate:"required"`
 	Answer     string `form:"answer" validate:"required"`
-	CategoryId string `form:"category_id" validate:"required"`
+	CategoryId int    `form:"category_id" validate:"required,min=1"`
 }
 
 type FaqResponse struct {
-	Id        int       `json:"id"`
-	Question  string    `json:"question"`
-	Answer    string    `json:"answer"`
-	Category  string    `json:"category"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id         int       `json:"id"`
+	Question   string    `json:"question"`
+	Answer     string    `json:"answer"`
+	CategoryId int       `json:"category_id"`
+	Category   string    `json:"category"`
+	Position   int       `json:"position"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// kategori faq beserta faq di dalamnya
+type FaqGroup struct {
+	CategoryFaq
+	Faqs []FaqResponse `json:"faqs"`
+}
+
+// daftar id sesuai urutan baru, opsional dibatasi satu kategori
+type ReorderRequest struct {
+	Ids        []int `form:"ids" json:"ids" validate:"required,min=1,dive,min=1"`
+	CategoryId int   `form:"category_id" json:"category_id" validate:"omitempty,min=1"`
 }
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type FaqVoteRequest struct {
+	Helpful  *bool  `form:"helpful" validate:"required"`
+	Feedback string `form:"feedback" validate:"max=1000"`
+}
+
+// ringkasan vote satu faq untuk laporan admin
+type FaqHelpfulness struct {
+	Id             int     `json:"id"`
+	Question       string  `json:"question"`
+	Category       string  `json:"category"`
+	Helpful        int     `json:"helpful"`
+	NotHelpful     int     `json:"not_helpful"`
+	TotalVotes     int     `json:"total_votes"`
+	UnhelpfulRatio float64 `json:"unhelpful_ratio"`
+	FeedbackCount  int     `json:"feedback_count"`
+}
+
+type FaqFeedback struct {
+	Id        int       `json:"id"`
+	FaqId     int       `json:"faq_id"`
+	Helpful   bool      `json:"helpful"`
+	Feedback  string    `json:"feedback"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FaqSearchMiss struct {
+	Query           string    `json:"query"`
+	Count           int       `json:"count"`
+	FirstSearchedAt time.Time `json:"first_searched_at"`
+	LastSearchedAt  time.Time `json:"last_searched_at"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+const (
+	InquiryStatusNew       = "new"
+	InquiryStatusContacted = "contacted"
+	InquiryStatusWon       = "won"
+	InquiryStatusLost      = "lost"
+)
+
+type Inquiry struct {
+	Id           int           `json:"id"`
+	ProductId    *int          `json:"product_id"`
+	ProductTitle string        `json:"product_title"`
+	Plan         string        `json:"plan"`
+	Name         string        `json:"name"`
+	Email        string        `json:"email"`
+	Phone        string        `json:"phone"`
+	Message      string        `json:"message"`
+	Status       string        `json:"status"`
+	AssignedTo   *int          `json:"assigned_to"`
+	AssigneeName string        `json:"assignee_name"`
+	Notes        []InquiryNote `json:"notes,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+type InquiryNote struct {
+	Id        int       `json:"id"`
+	UserId    *int      `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InquiryRequest struct {
+	Name    string `form:"name" validate:"required,min=2,max=100"`
+	Email   string `form:"email" validate:"required,email,max=255"`
+	Phone   string `form:"phone" validate:"omitempty,min=6,max=30"`
+	Message string `form:"message" validate:"max=5000"`
+	Plan    string `form:"plan" validate:"omitempty,max=100"` // contoh: bulanan / tahunan
+}
+
+type InquiryStatusRequest struct {
+	Status string `form:"status" validate:"required,oneof=new contacted won lost"`
+}
+
+type InquiryAssignRequest struct {
+	UserId int `form:"user_id" validate:"omitempty,min=1"` // kosong = lepas assignment
+}
+
+type InquiryNoteRequest struct {
+	Note string `form:"note" validate:"required,max=5000"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusExpired  = "expired"
+	InvitationStatusRevoked  = "revoked"
+)
+
+type Invitation struct {
+	Id             int        `json:"id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"`
+	InvitedBy      *int       `json:"invited_by"`
+	InvitedByName  string     `json:"invited_by_name"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	AcceptedUserId *int       `json:"accepted_user_id"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type InvitationRequest struct {
+	Email          string `form:"email" validate:"required,email,max=255"`
+	Role           string `form:"role" validate:"required,max=50"`
+	ExpiresInHours int    `form:"expires_in_hours" validate:"omitempty,min=1,max=720"`
+}
+
+// data user diisi sendiri oleh yang diundang, email + role dari undangan
+type AcceptInvitationRequest struct {
+	Token    string `form:"token" validate:"required"`
+	Name     string `form:"name" validate:"required,min=2"`
+	Password string `form:"password" validate:"required,min=8,max=72"`
+}
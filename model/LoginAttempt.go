@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	ThrottleScopeAccount = "account"
+	ThrottleScopeIP      = "ip"
+)
+
+type LoginAttempt struct {
+	Id        int       `json:"id"`
+	Email     string    `json:"email"`
+	UserId    *int      `json:"user_id"`
+	IpHash    string    `json:"ip_hash"`
+	UserAgent string    `json:"user_agent"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LoginLockout struct {
+	Scope         string     `json:"scope"`
+	Key           string     `json:"key"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	Locked        bool       `json:"locked"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	MailStatusPending = "pending"
+	MailStatusSent    = "sent"
+	MailStatusFailed  = "failed"
+)
+
+type MailOutbox struct {
+	Id            int        `json:"id"`
+	Template      string     `json:"template"`
+	To            string     `json:"to"`
+	ReplyTo       string     `json:"reply_to"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package model
+
+type ForgotPasswordRequest struct {
+	Email string `form:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `form:"token" validate:"required"`
+	Password string `form:"password" validate:"required,min=8,max=72"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `form:"current_password" validate:"required"`
+	Password        string `form:"password" validate:"required,min=8,max=72,nefield=CurrentPassword"`
+}
@@ -1,22 +1,80 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/utils"
+)
+
+const (
+	DiscountTypePercent = "percent"
+	DiscountTypeFixed   = "fixed"
+)
 
 type Product struct {
-	Id          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Price       int       `json:"price"`
-	Discount    int       `json:"discount"`
-	Type        string    `json:"type"`
-	Icon        string    `json:"icon"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id               int              `json:"id"`
+	Title            string           `json:"title"`
+	Description      string           `json:"description"`
+	Currency         string           `json:"currency"`
+	Price            utils.Money      `json:"price"`
+	DiscountType     string           `json:"discount_type"`
+	Discount         utils.Money      `json:"discount"` // persen (0-100) atau nominal sesuai discount_type
+	DiscountStartsAt *time.Time       `json:"discount_starts_at"`
+	DiscountEndsAt   *time.Time       `json:"discount_ends_at"`
+	DiscountActive   bool             `json:"discount_active"`
+	DiscountAmount   utils.Money      `json:"discount_amount"`
+	FinalPrice       utils.Money      `json:"final_price"`
+	Type             string           `json:"type"`
+	Icon             string           `json:"icon"`
+	Highlighted      bool             `json:"highlighted"` // paket yang direkomendasikan
+	Features         []ProductFeature `json:"features"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
 }
 
 type ProductRequest struct {
-	Title       string `form:"title" validate:"required"`
-	Description string `form:"description" validate:"required"`
-	Price       int    `form:"price" validate:"required"`
-	Type        string `form:"type" validate:"required"`
+	Title            string `form:"title" validate:"required"`
+	Description      string `form:"description" validate:"required"`
+	Price            string `form:"price" validate:"required"`
+	Currency         string `form:"currency" validate:"omitempty,len=3,alpha"`
+	DiscountType     string `form:"discount_type" validate:"omitempty,oneof=percent fixed"` // default fixed
+	Discount         string `form:"discount"`
+	DiscountStartsAt string `form:"discount_starts_at"` // RFC3339 atau YYYY-MM-DD
+	DiscountEndsAt   string `form:"discount_ends_at"`
+	Type             string `form:"type" validate:"required"`
+	Highlighted      bool   `form:"highlighted"`
+}
+
+type ProductFeature struct {
+	Id        int       `json:"id"`
+	ProductId int       `json:"product_id"`
+	Label     string    `json:"label"`
+	Value     string    `json:"value"`
+	Included  bool      `json:"included"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ProductFeatureRequest struct {
+	Label    string `form:"label" validate:"required,max=255"`
+	Value    string `form:"value" validate:"max=255"`
+	Included *bool  `form:"included"` // default true
+}
+
+// satu sel matriks perbandingan, nil jika produk tidak punya fitur tsb
+type ProductComparisonCell struct {
+	Included bool   `json:"included"`
+	Value    string `json:"value"`
+}
+
+type ProductComparisonRow struct {
+	Label  string                   `json:"label"`
+	Values []*ProductComparisonCell `json:"values"` // urutan sama dengan products
+}
+
+type ProductComparison struct {
+	Type     string                 `json:"type"`
+	Products []Product              `json:"products"`
+	Features []ProductComparisonRow `json:"features"`
 }
@@ -0,0 +1,30 @@
+package model
+
+// field SEO yang disimpan di pages, articles dan services
+type SeoFields struct {
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	CanonicalUrl    string `json:"canonical_url"`
+	OgImage         string `json:"og_image"`
+	Noindex         bool   `json:"noindex"`
+}
+
+type SeoRequest struct {
+	MetaTitle       string `form:"meta_title" validate:"omitempty,max=255"`
+	MetaDescription string `form:"meta_description" validate:"omitempty,max=500"`
+	CanonicalUrl    string `form:"canonical_url" validate:"omitempty,url"`
+	Noindex         bool   `form:"noindex"`
+}
+
+// metadata SEO final (sudah diisi default) untuk response detail
+type SeoMeta struct {
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	CanonicalUrl  string `json:"canonical_url"`
+	OgImage       string `json:"og_image"`
+	OgType        string `json:"og_type"`
+	Robots        string `json:"robots"`
+	Noindex       bool   `json:"noindex"`
+	PublishedTime string `json:"published_time,omitempty"`
+	ModifiedTime  string `json:"modified_time,omitempty"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+type StatsTotals struct {
+	Pages             int `json:"pages"`
+	Abouts            int `json:"abouts"`
+	Services          int `json:"services"`
+	Portfolios        int `json:"portfolios"`
+	Products          int `json:"products"`
+	Contacts          int `json:"contacts"`
+	Users             int `json:"users"`
+	CategoryArticles  int `json:"category_articles"`
+	Articles          int `json:"articles"`
+	CategoryFaqs      int `json:"category_faqs"`
+	Faqs              int `json:"faqs"`
+	ArticleViewsTotal int `json:"article_views_total"`
+}
+
+type TopArticle struct {
+	Id       int    `json:"id"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	Category string `json:"category"`
+	Views    int    `json:"views"`
+}
+
+type ViewsPerDay struct {
+	Day   string `json:"day"`
+	Views int    `json:"views"`
+}
+
+type CountByLabel struct {
+	Id    int    `json:"id"`
+	Label string `json:"label"`
+	Total int    `json:"total"`
+}
+
+type StatsResponse struct {
+	From               string         `json:"from"`
+	To                 string         `json:"to"`
+	Totals             StatsTotals    `json:"totals"`
+	TopArticles        []TopArticle   `json:"top_articles"`
+	ViewsPerDay        []ViewsPerDay  `json:"views_per_day"`
+	ArticlesByCategory []CountByLabel `json:"articles_by_category"`
+	ArticlesByAuthor   []CountByLabel `json:"articles_by_author"`
+	NewestUsers        []UserResponse `json:"newest_users"`
+	GeneratedAt        time.Time      `json:"generated_at"`
+}
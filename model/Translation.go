@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+type Translation struct {
+	Id         int       `json:"id"`
+	Resource   string    `json:"resource"`
+	ResourceId int       `json:"resource_id"`
+	Locale     string    `json:"locale"`
+	Field      string    `json:"field"`
+	Value      string    `json:"value"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// terjemahan satu resource dikelompokkan per locale
+type TranslationResponse struct {
+	Locale    string            `json:"locale"`
+	Fields    map[string]string `json:"fields"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	Required               bool       `json:"required"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// code boleh kode TOTP 6 digit atau recovery code
+type TwoFactorCodeRequest struct {
+	Code string `form:"code" validate:"required,max=32"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `form:"password" validate:"required"`
+	Code     string `form:"code" validate:"required,max=32"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `form:"challenge_token" validate:"required"`
+	Code           string `form:"code" validate:"required,max=32"`
+}
@@ -2,6 +2,12 @@ package model
 
 import "time"
 
+// role dengan akses pengelolaan user; role lain (penulis) bebas ditentukan admin
+const (
+	RoleSuperadmin = "superadmin"
+	RoleAdmin      = "admin"
+)
+
 type User struct {
 	Id        int       `json:"id"`
 	Name      string    `json:"name" validate:"required"`
@@ -26,6 +32,14 @@ type UserRequestUpdate struct {
 	Role  string `form:"role" validate:"required"`
 }
 
+// edit profil sendiri: role & password tidak bisa diubah lewat sini,
+// current_password wajib jika email diganti
+type UserProfileRequest struct {
+	Name            string `form:"name" validate:"required,min=2"`
+	Email           string `form:"email" validate:"required,email"`
+	CurrentPassword string `form:"current_password"`
+}
+
 type UserResponse struct {
 	Id        int       `json:"id"`
 	Name      string    `json:"name"`
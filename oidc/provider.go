@@ -0,0 +1,303 @@
+// Package oidc adalah client OpenID Connect minimal untuk login SSO admin:
+// authorization code flow + PKCE (S256), discovery, JWKS dan verifikasi ID token.
+//
+// Untuk development jalankan provider tiruan lalu arahkan config ke sana:
+//
+//	go run ./cmd/mockoidc -email admin@codetech.test
+//	OIDC_ISSUER=http://localhost:9090 OIDC_CLIENT_ID=codetech go run .
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// metadata discovery & JWKS di-cache selama ini
+	CacheTTL = time.Hour
+	// JWKS diambil ulang saat kid tidak dikenal (rotasi kunci), paling sering sekali per interval ini
+	JWKSRefreshInterval = time.Minute
+	// toleransi selisih jam dengan identity provider
+	Leeway = time.Minute
+)
+
+// algoritma ID token yang diterima
+var validMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims adalah isi ID token yang dipakai untuk mencocokkan user
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	// identity provider melaporkan login multi-faktor (claim amr berisi "mfa", RFC 8176)
+	MFA bool
+}
+
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	HTTPClient   *http.Client
+
+	mu     sync.Mutex
+	meta   *metadata
+	metaAt time.Time
+	keys   map[string]interface{}
+	keysAt time.Time
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultProvider *Provider
+)
+
+// Default membuat Provider dari config, nil jika OIDC_ISSUER / OIDC_CLIENT_ID kosong
+func Default() *Provider {
+	defaultOnce.Do(func() {
+		if config.OIDCIssuer == "" || config.OIDCClientID == "" {
+			return
+		}
+		defaultProvider = &Provider{
+			Issuer:       config.OIDCIssuer,
+			ClientID:     config.OIDCClientID,
+			ClientSecret: config.OIDCClientSecret,
+			RedirectURL:  config.OIDCRedirectURL,
+			Scopes:       []string{"openid", "email", "profile"},
+			HTTPClient:   &http.Client{Timeout: 10 * time.Second},
+		}
+	})
+	return defaultProvider
+}
+
+// PKCEChallenge menghitung code_challenge S256 dari code_verifier (RFC 7636)
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s: status %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// discovery dari <issuer>/.well-known/openid-configuration, issuer harus sama persis
+func (p *Provider) metadata(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil && time.Since(p.metaAt) < CacheTTL {
+		return p.meta, nil
+	}
+
+	var meta metadata
+	if err := p.getJSON(ctx, p.Issuer+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, err
+	}
+	if strings.TrimRight(meta.Issuer, "/") != p.Issuer {
+		return nil, fmt.Errorf("oidc: issuer mismatch: %q", meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("oidc: incomplete provider metadata")
+	}
+
+	p.meta, p.metaAt = &meta, time.Now()
+	return p.meta, nil
+}
+
+// AuthCodeURL membuat URL halaman login identity provider
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.ClientID},
+		"redirect_uri":          {p.RedirectURL},
+		"scope":                 {strings.Join(p.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {PKCEChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange menukar authorization code (+ code_verifier) dengan ID token
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	// confidential client memakai client_secret_basic, public client cukup client_id
+	if p.ClientSecret == "" {
+		form.Set("client_id", p.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("oidc: token endpoint: status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("oidc: token endpoint: status %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("oidc: token response has no id_token")
+	}
+	return body.IDToken, nil
+}
+
+// kunci publik sesuai kid, JWKS diambil ulang jika kid belum dikenal
+func (p *Provider) key(ctx context.Context, kid string) (interface{}, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok && time.Since(p.keysAt) < CacheTTL {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysAt) < JWKSRefreshInterval {
+		return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+	}
+
+	var set jwkSet
+	if err := p.getJSON(ctx, meta.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	p.keys, p.keysAt = set.publicKeys(), time.Now()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+}
+
+// VerifyIDToken memeriksa tanda tangan, iss, aud, exp dan nonce ID token
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := p.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if !keyMatchesMethod(key, token.Method) {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		return key, nil
+	},
+		jwt.WithValidMethods(validMethods),
+		jwt.WithIssuer(p.Issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(Leeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid id token: %w", err)
+	}
+
+	if got, _ := claims["nonce"].(string); nonce == "" || got != nonce {
+		return nil, errors.New("oidc: nonce mismatch")
+	}
+	// token untuk banyak audience wajib menyebut client ini sebagai azp
+	if azp, ok := claims["azp"].(string); ok && azp != p.ClientID {
+		return nil, errors.New("oidc: authorized party mismatch")
+	}
+
+	result := &Claims{}
+	result.Subject, _ = claims["sub"].(string)
+	result.Email, _ = claims["email"].(string)
+	result.Name, _ = claims["name"].(string)
+	// beberapa provider mengirim email_verified sebagai string
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		result.EmailVerified = v
+	case string:
+		result.EmailVerified = v == "true"
+	}
+	amr, _ := claims["amr"].([]interface{})
+	for _, method := range amr {
+		if method == "mfa" {
+			result.MFA = true
+		}
+	}
+	if result.Subject == "" {
+		return nil, errors.New("oidc: id token has no subject")
+	}
+	return result, nil
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "strings"
+
+// format API key: ctk_<prefix>_<rahasia>, prefix disimpan apa adanya untuk
+// pencarian dan ditampilkan di daftar key, rahasia hanya disimpan hash-nya
+const apiKeyTag = "ctk"
+
+// NewAPIKey membuat API key baru, return prefix dan key lengkap
+func NewAPIKey() (string, string) {
+	prefix := strings.NewReplacer("-", "x", "_", "x").Replace(RandomToken(6))
+	return prefix, apiKeyTag + "_" + prefix + "_" + RandomToken(32)
+}
+
+// ParseAPIKey mengambil prefix dari API key, false jika formatnya salah
+func ParseAPIKey(key string) (string, bool) {
+	parts := strings.SplitN(strings.TrimSpace(key), "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyTag || len(parts[1]) != 8 || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// batas jumlah entry per cache, entry acak dibuang jika penuh
+const cacheMaxItems = 10000
+
+type cacheItem struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Cache adalah cache in-memory sederhana dengan TTL per entry
+type Cache struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	items     map[string]cacheItem
+	lastSweep time.Time
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, items: make(map[string]cacheItem), lastSweep: time.Now()}
+}
+
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, ok := c.items[key]
+	if !ok || time.Now().After(item.expiresAt) {
+		return nil, false
+	}
+	return item.value, true
+}
+
+func (c *Cache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	// bersihkan entry kedaluwarsa paling sering sekali per TTL
+	if now.Sub(c.lastSweep) > c.ttl {
+		for k, item := range c.items {
+			if now.After(item.expiresAt) {
+				delete(c.items, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	if _, ok := c.items[key]; !ok && len(c.items) >= cacheMaxItems {
+		for k := range c.items {
+			delete(c.items, k)
+			break
+		}
+	}
+
+	c.items[key] = cacheItem{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]cacheItem)
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ContentFormatHTML     = "html"
+	ContentFormatMarkdown = "markdown"
+	ContentFormatPlain    = "plain"
+
+	// rata-rata kecepatan baca (kata per menit)
+	wordsPerMinute = 200
+)
+
+// SanitizeContent dipakai saat menyimpan konten: HTML disanitasi, Markdown
+// disimpan apa adanya karena akan di-escape saat dirender
+func SanitizeContent(source, format string) string {
+	if format == ContentFormatMarkdown {
+		return source
+	}
+	return SanitizeHTML(source)
+}
+
+// RenderContent mengubah konten tersimpan ke format output yang diminta
+func RenderContent(source, sourceFormat, outputFormat string) string {
+	switch outputFormat {
+	case ContentFormatMarkdown:
+		if sourceFormat == ContentFormatMarkdown {
+			return source
+		}
+		return HTMLToMarkdown(source)
+	case ContentFormatPlain:
+		return HTMLToPlainText(RenderContent(source, sourceFormat, ContentFormatHTML))
+	default:
+		if sourceFormat == ContentFormatMarkdown {
+			return MarkdownToHTML(source)
+		}
+		return SanitizeHTML(source)
+	}
+}
+
+// Excerpt memotong teks biasa di batas kata terdekat
+func Excerpt(plain string, maxChars int) string {
+	plain = strings.Join(strings.Fields(plain), " ")
+	if utf8.RuneCountInString(plain) <= maxChars {
+		return plain
+	}
+
+	runes := []rune(plain)
+	cut := string(runes[:maxChars])
+	if idx := strings.LastIndex(cut, " "); idx > maxChars/2 {
+		cut = cut[:idx]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "…"
+}
+
+// ReadingTime mengestimasi waktu baca dalam menit (minimal 1)
+func ReadingTime(plain string) int {
+	words := len(strings.Fields(plain))
+	minutes := int(math.Ceil(float64(words) / wordsPerMinute))
+	if minutes < 1 {
+		return 1
+	}
+	return minutes
+}
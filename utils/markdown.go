@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+var (
+	mdHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdUnordered = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdOrdered   = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	mdRule      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	mdImage     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+&#34;([^&]*)&#34;)?\)`)
+	mdLink      = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+&#34;([^&]*)&#34;)?\)`)
+	mdBold      = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdItalic    = regexp.MustCompile(`(^|[^*\w])[*_]([^*_\s][^*_]*?)[*_]`)
+	mdStrike    = regexp.MustCompile(`~~(.+?)~~`)
+	mdBlankRuns = regexp.MustCompile(`\n{3,}`)
+)
+
+// MarkdownToHTML merender subset Markdown (heading, paragraf, list, quote,
+// code block, link, gambar, bold/italic) ke HTML yang sudah disanitasi
+func MarkdownToHTML(input string) string {
+	lines := strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n")
+
+	var b strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			b.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			b.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			closeList()
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code")
+			if lang != "" {
+				b.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+			}
+			b.WriteString(">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+
+		case mdRule.MatchString(trimmed):
+			flushParagraph()
+			closeList()
+			b.WriteString("<hr>\n")
+
+		case mdHeading.MatchString(trimmed):
+			flushParagraph()
+			closeList()
+			m := mdHeading.FindStringSubmatch(trimmed)
+			tag := "h" + string(rune('0'+len(m[1])))
+			b.WriteString("<" + tag + ">" + renderInline(m[2]) + "</" + tag + ">\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			closeList()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			b.WriteString("<blockquote>\n" + MarkdownToHTML(strings.Join(quote, "\n")) + "</blockquote>\n")
+
+		case mdUnordered.MatchString(line):
+			flushParagraph()
+			openList("ul")
+			b.WriteString("<li>" + renderInline(mdUnordered.FindStringSubmatch(line)[1]) + "</li>\n")
+
+		case mdOrdered.MatchString(line):
+			flushParagraph()
+			openList("ol")
+			b.WriteString("<li>" + renderInline(mdOrdered.FindStringSubmatch(line)[1]) + "</li>\n")
+
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return SanitizeHTML(b.String())
+}
+
+// render elemen inline; teks di-escape dulu sehingga HTML mentah tidak lolos
+func renderInline(text string) string {
+	// pisahkan code span agar isinya tidak diproses
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + escaped + "</code>"
+			continue
+		}
+		escaped = mdImage.ReplaceAllString(escaped, `<img src="$2" alt="$1" title="$3">`)
+		escaped = mdLink.ReplaceAllString(escaped, `<a href="$2" title="$3">$1</a>`)
+		escaped = mdBold.ReplaceAllString(escaped, "<strong>$2</strong>")
+		escaped = mdItalic.ReplaceAllString(escaped, "$1<em>$2</em>")
+		escaped = mdStrike.ReplaceAllString(escaped, "<del>$1</del>")
+		escaped = strings.ReplaceAll(escaped, "\n", "<br>")
+		if i%2 == 1 {
+			// backtick tanpa pasangan
+			escaped = "`" + escaped
+		}
+		parts[i] = escaped
+	}
+	return strings.Join(parts, "")
+}
+
+// HTMLToMarkdown mengonversi HTML (hasil sanitasi) kembali ke Markdown
+func HTMLToMarkdown(input string) string {
+	z := nethtml.NewTokenizer(strings.NewReader(SanitizeHTML(input)))
+	var b strings.Builder
+	var hrefs []string
+	var lists []string
+	var quotes []int
+	inPre := false
+
+	for {
+		tt := z.Next()
+		if tt == nethtml.ErrorToken {
+			break
+		}
+
+		tok := z.Token()
+		switch tt {
+		case nethtml.TextToken:
+			if inPre {
+				b.WriteString(tok.Data)
+			} else {
+				text := strings.Join(strings.Fields(tok.Data), " ")
+				if strings.TrimLeft(tok.Data, " \n\t") != tok.Data && !endsWithSpace(&b) {
+					b.WriteString(" ")
+				}
+				b.WriteString(text)
+				if text != "" && strings.TrimRight(tok.Data, " \n\t") != tok.Data {
+					b.WriteString(" ")
+				}
+			}
+
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			switch tok.Data {
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				b.WriteString("\n\n" + strings.Repeat("#", int(tok.Data[1]-'0')) + " ")
+			case "p", "div", "figure", "table":
+				b.WriteString("\n\n")
+			case "tr":
+				b.WriteString("\n")
+			case "td", "th":
+				b.WriteString(" | ")
+			case "br":
+				b.WriteString("  \n")
+			case "hr":
+				b.WriteString("\n\n---\n\n")
+			case "strong", "b":
+				b.WriteString("**")
+			case "em", "i":
+				b.WriteString("_")
+			case "del", "s":
+				b.WriteString("~~")
+			case "code":
+				if !inPre {
+					b.WriteString("`")
+				}
+			case "pre":
+				inPre = true
+				b.WriteString("\n\n```\n")
+			case "blockquote":
+				b.WriteString("\n\n")
+				quotes = append(quotes, b.Len())
+			case "ul", "ol":
+				lists = append(lists, tok.Data)
+				b.WriteString("\n")
+			case "li":
+				b.WriteString("\n")
+				if len(lists) > 0 && lists[len(lists)-1] == "ol" {
+					b.WriteString("1. ")
+				} else {
+					b.WriteString("- ")
+				}
+			case "a":
+				hrefs = append(hrefs, attrValue(tok, "href"))
+				b.WriteString("[")
+			case "img":
+				b.WriteString("![" + attrValue(tok, "alt") + "](" + attrValue(tok, "src") + ")")
+			}
+
+		case nethtml.EndTagToken:
+			switch tok.Data {
+			case "h1", "h2", "h3", "h4", "h5", "h6", "p", "div", "figure", "table":
+				b.WriteString("\n\n")
+			case "blockquote":
+				if len(quotes) > 0 {
+					// prefix setiap baris isi quote dengan "> "
+					start := quotes[len(quotes)-1]
+					quotes = quotes[:len(quotes)-1]
+					current := b.String()
+					inner := strings.TrimSpace(mdBlankRuns.ReplaceAllString(current[start:], "\n\n"))
+					b.Reset()
+					b.WriteString(current[:start])
+					b.WriteString("> " + strings.ReplaceAll(inner, "\n", "\n> "))
+				}
+				b.WriteString("\n\n")
+			case "strong", "b":
+				b.WriteString("**")
+			case "em", "i":
+				b.WriteString("_")
+			case "del", "s":
+				b.WriteString("~~")
+			case "code":
+				if !inPre {
+					b.WriteString("`")
+				}
+			case "pre":
+				inPre = false
+				b.WriteString("\n```\n\n")
+			case "ul", "ol":
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				b.WriteString("\n\n")
+			case "a":
+				href := ""
+				if len(hrefs) > 0 {
+					href = hrefs[len(hrefs)-1]
+					hrefs = hrefs[:len(hrefs)-1]
+				}
+				b.WriteString("](" + href + ")")
+			}
+		}
+	}
+
+	return strings.TrimSpace(mdBlankRuns.ReplaceAllString(b.String(), "\n\n"))
+}
+
+func endsWithSpace(b *strings.Builder) bool {
+	s := b.String()
+	return s == "" || strings.HasSuffix(s, " ") || strings.HasSuffix(s, "\n")
+}
+
+func attrValue(tok nethtml.Token, key string) string {
+	for _, attr := range tok.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"paragraph", "Halo dunia", "<p>Halo dunia</p>\n"},
+		{"heading", "## Judul ##", "<h2>Judul</h2>\n"},
+		{"emphasis", "**tebal** dan *miring* dan ~~coret~~", "<p><strong>tebal</strong> dan <em>miring</em> dan <del>coret</del></p>\n"},
+		{"line break", "a\nb", "<p>a<br>b</p>\n"},
+		{"unordered list", "- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n"},
+		{"ordered list", "1. a\n2. b", "<ol>\n<li>a</li>\n<li>b</li>\n</ol>\n"},
+		{"rule", "---", "<hr>\n"},
+		{"blockquote", "> kutipan", "<blockquote>\n<p>kutipan</p>\n</blockquote>\n"},
+		{"code block", "```go\nif a < b {}\n```", "<pre><code class=\"language-go\">if a &lt; b {}</code></pre>\n"},
+		{"code span", "pakai `<b>` saja", "<p>pakai <code>&lt;b&gt;</code> saja</p>\n"},
+		{"link", "[situs](https://codetech.id)", "<p><a href=\"https://codetech.id\" rel=\"nofollow noopener noreferrer\">situs</a></p>\n"},
+		{"link title", "[situs](https://codetech.id \"Codetech\")", "<p><a href=\"https://codetech.id\" title=\"Codetech\" rel=\"nofollow noopener noreferrer\">situs</a></p>\n"},
+		{"image", "![logo](/logo.png)", "<p><img src=\"/logo.png\" alt=\"logo\"></p>\n"},
+
+		// HTML mentah dan URL berbahaya tidak lolos
+		{"raw script", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"raw event handler", "<img src=x onerror=alert(1)>", "<p>&lt;img src=x onerror=alert(1)&gt;</p>\n"},
+		{"javascript link", "[x](javascript:alert%281%29)", "<p><a rel=\"nofollow noopener noreferrer\">x</a></p>\n"},
+		{"data image", "![x](data:image/svg+xml;base64,PHN2Zz4=)", "<p><img alt=\"x\"></p>\n"},
+		{"entity encoded link", "[x](javascript&#58;alert(1))", "<p><a href=\"javascript&amp;#58;alert(1\" rel=\"nofollow noopener noreferrer\">x</a>)</p>\n"},
+		{"quote breaks attribute", "![\" onerror=\"alert(1)](/a.png)", "<p><img src=\"/a.png\" alt=\"&#34; onerror=&#34;alert(1)\"></p>\n"},
+		{"unclosed code fence", "```\n<div>", "<pre><code>&lt;div&gt;</code></pre>\n"},
+	}
+	for _, tt := range tests {
+		if got := MarkdownToHTML(tt.input); got != tt.want {
+			t.Errorf("%s: MarkdownToHTML(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownToHTMLNoActiveContent(t *testing.T) {
+	inputs := []string{
+		"[a](javascript:alert(1))",
+		"[a](JAVASCRIPT:alert(1))",
+		"[a]( javascript:alert(1))",
+		"![a](javascript:alert(1))",
+		"<a href=\"javascript:alert(1)\">a</a>",
+		"**<svg onload=alert(1)>**",
+		"> <iframe src=javascript:alert(1)>",
+	}
+	for _, input := range inputs {
+		got := strings.ToLower(MarkdownToHTML(input))
+		for _, bad := range []string{"<script", "<svg", "<iframe", "href=\"javascript:", "src=\"javascript:", " onload=\"", " onerror=\""} {
+			if strings.Contains(got, bad) {
+				t.Errorf("MarkdownToHTML(%q) = %q contains %q", input, got, bad)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money menyimpan nominal dalam satuan sen (2 desimal) agar tidak ada pembulatan float.
+// Di database disimpan sebagai NUMERIC(14,2), di JSON sebagai string "150000.00".
+type Money int64
+
+var ErrInvalidMoney = errors.New("invalid amount, expected a number with at most 2 decimals")
+
+// ParseMoney membaca "1500", "1500.5" atau "1500.50" (titik sebagai pemisah desimal)
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidMoney
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || len(frac) > 2 || (hasFrac && frac == "") {
+		return 0, ErrInvalidMoney
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	for _, r := range whole + frac {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalidMoney
+		}
+	}
+
+	// batas NUMERIC(14,2): 12 digit bilangan bulat
+	if len(strings.TrimLeft(whole, "0")) > 12 {
+		return 0, ErrInvalidMoney
+	}
+
+	v, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidMoney
+	}
+	if negative {
+		v = -v
+	}
+	return Money(v), nil
+}
+
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// Percent menghitung m * percent / 100 (percent juga dalam 2 desimal), dibulatkan ke sen
+// terdekat; setengah sen dibulatkan menjauhi nol sehingga nilai negatif simetris
+func (m Money) Percent(percent Money) Money {
+	v := int64(m) * int64(percent)
+	if v < 0 {
+		return -Money((-v + 5000) / 10000)
+	}
+	return Money((v + 5000) / 10000)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	v, err := ParseMoney(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Scan membaca kolom NUMERIC dari database
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Money", src)
+}
+
+func (m *Money) scanString(s string) error {
+	// NUMERIC tanpa skala bisa punya lebih dari 2 desimal, buang nol di belakang
+	if whole, frac, ok := strings.Cut(s, "."); ok && len(frac) > 2 {
+		frac = strings.TrimRight(frac, "0")
+		s = whole
+		if frac != "" {
+			s += "." + frac
+		}
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value menulis nominal ke database sebagai teks desimal
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
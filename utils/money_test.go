@@ -0,0 +1,133 @@
+package utils
+
+import "testing"
+
+func TestParseMoney(t *testing.T) {
+	valid := []struct {
+		input string
+		want  Money
+	}{
+		{"0", 0},
+		{"1500", 150000},
+		{"1.5", 150},
+		{"1.50", 150},
+		{"1.05", 105},
+		{"0.01", 1},
+		{" 12.30 ", 1230},
+		{"-1.5", -150},
+		{"007", 700},
+		{"999999999999.99", 99999999999999},
+		{"000999999999999.99", 99999999999999},
+	}
+	for _, tt := range valid {
+		got, err := ParseMoney(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = (%d, %v), want %d", tt.input, got, err, tt.want)
+		}
+	}
+
+	invalid := []string{
+		"", " ", "-", "1.", ".5", "1.234", "1,5", "1e3", "+1", "--1", "1.-5", "abc", "1 000",
+		"1000000000000",    // 13 digit bilangan bulat
+		"1000000000000.00", // melebihi NUMERIC(14,2)
+		"99999999999999999999",
+	}
+	for _, input := range invalid {
+		if got, err := ParseMoney(input); err != ErrInvalidMoney {
+			t.Errorf("ParseMoney(%q) = (%d, %v), want ErrInvalidMoney", input, got, err)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{150, "1.50"},
+		{15000000, "150000.00"},
+		{-5, "-0.05"},
+		{-150, "-1.50"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(tt.m), got, tt.want)
+		}
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		m, percent Money
+		want       Money
+	}{
+		{10000, 1000, 1000},   // 10% dari 100.00 = 10.00
+		{10000, 10000, 10000}, // 100%
+		{10000, 0, 0},
+		{999, 1000, 100},  // 9.99 * 10% = 0.999 -> 1.00
+		{105, 5000, 53},   // 1.05 * 50% = 0.525 -> 0.53
+		{101, 5000, 51},   // 0.505 -> 0.51 (setengah dibulatkan ke atas)
+		{1, 4900, 0},      // 0.0049 -> 0.00
+		{-105, 5000, -53}, // simetris dengan nilai positif
+		{-101, 5000, -51},
+		{105, -5000, -53},
+		{-1, 4900, 0},
+		{1999900, 1250, 249988}, // 19999.00 * 12.5% = 2499.875 -> 2499.88
+	}
+	for _, tt := range tests {
+		if got := tt.m.Percent(tt.percent); got != tt.want {
+			t.Errorf("Money(%d).Percent(%d) = %d, want %d", int64(tt.m), int64(tt.percent), int64(got), int64(tt.want))
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		src  interface{}
+		want Money
+	}{
+		{nil, 0},
+		{[]byte("150000.00"), 15000000},
+		{[]byte("1.5"), 150},
+		{"1.50", 150},
+		{"1.500", 150},  // NUMERIC tanpa skala, nol di belakang
+		{"1.0000", 100}, // semua desimal nol
+		{"12.3400", 1234},
+		{"-2.500", -250},
+		{"7", 700},
+		{int64(7), 700},
+	}
+	for _, tt := range tests {
+		var m Money
+		if err := m.Scan(tt.src); err != nil || m != tt.want {
+			t.Errorf("Scan(%#v) = (%d, %v), want %d", tt.src, int64(m), err, int64(tt.want))
+		}
+	}
+
+	for _, src := range []interface{}{"1.234", []byte("1.2345"), "abc", 1.5, true} {
+		var m Money
+		if err := m.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) = %d, want error", src, int64(m))
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := Money(150).MarshalJSON()
+	if err != nil || string(data) != `"1.50"` {
+		t.Errorf("MarshalJSON = (%s, %v), want \"1.50\"", data, err)
+	}
+
+	var m Money
+	if err := m.UnmarshalJSON([]byte(`"1.5"`)); err != nil || m != 150 {
+		t.Errorf("UnmarshalJSON(\"1.5\") = (%d, %v), want 150", int64(m), err)
+	}
+	if err := m.UnmarshalJSON([]byte(`2`)); err != nil || m != 200 {
+		t.Errorf("UnmarshalJSON(2) = (%d, %v), want 200", int64(m), err)
+	}
+	if err := m.UnmarshalJSON([]byte(`"1.234"`)); err == nil {
+		t.Error("UnmarshalJSON(\"1.234\") accepted")
+	}
+}
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// tag yang diizinkan beserta atribut yang boleh dipakai
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "span": nil, "div": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil,
+	"sub": nil, "sup": nil, "mark": nil, "small": nil,
+	"blockquote": nil, "pre": nil, "code": {"class"},
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil, "th": {"colspan", "rowspan"}, "td": {"colspan", "rowspan"},
+	"figure": nil, "figcaption": nil,
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title", "width", "height"},
+}
+
+// tag yang dibuang beserta seluruh isinya
+var droppedContentTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "svg": true, "math": true, "textarea": true, "select": true,
+}
+
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// SafeURL mengecek apakah URL aman dipakai di href/src (tanpa javascript:, data:, dsb)
+func SafeURL(raw string) bool {
+	u := strings.ToLower(strings.TrimSpace(raw))
+	// buang karakter kontrol/spasi yang bisa dipakai untuk menyamarkan skema
+	u = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == ' ' {
+			return -1
+		}
+		return r
+	}, u)
+
+	if u == "" {
+		return false
+	}
+	if strings.HasPrefix(u, "/") || strings.HasPrefix(u, "#") || strings.HasPrefix(u, "?") {
+		return true
+	}
+
+	colon := strings.Index(u, ":")
+	if colon == -1 {
+		// URL relatif tanpa skema
+		return true
+	}
+	// ':' setelah '/', '?' atau '#' bukan bagian dari skema
+	if slash := strings.IndexAny(u, "/?#"); slash != -1 && slash < colon {
+		return true
+	}
+
+	scheme := u[:colon]
+	return scheme == "http" || scheme == "https" || scheme == "mailto" || scheme == "tel"
+}
+
+// SanitizeHTML membersihkan HTML dengan allow-list tag dan atribut; tag yang
+// tidak ditutup ditutup di akhir dan tag penutup tanpa pasangan dibuang
+func SanitizeHTML(input string) string {
+	z := html.NewTokenizer(strings.NewReader(input))
+	var b strings.Builder
+	var open []string
+	skipDepth := 0
+
+	// tutup tag yang masih terbuka sampai (termasuk) indeks from
+	closeFrom := func(from int) {
+		for i := len(open) - 1; i >= from; i-- {
+			b.WriteString("</" + open[i] + ">")
+		}
+		open = open[:from]
+	}
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			closeFrom(0)
+			return b.String()
+
+		case html.TextToken:
+			if skipDepth == 0 {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if droppedContentTags[tok.Data] {
+				if tt == html.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+
+			attrs, ok := allowedTags[tok.Data]
+			if !ok {
+				continue
+			}
+
+			b.WriteString("<" + tok.Data)
+			for _, attr := range tok.Attr {
+				if !containsString(attrs, attr.Key) || (attr.Key == "title" && attr.Val == "") {
+					continue
+				}
+				if (attr.Key == "href" || attr.Key == "src") && !SafeURL(attr.Val) {
+					continue
+				}
+				b.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+			}
+			if tok.Data == "a" {
+				b.WriteString(` rel="nofollow noopener noreferrer"`)
+			}
+			b.WriteString(">")
+			if !voidTags[tok.Data] {
+				if tt == html.SelfClosingTagToken {
+					b.WriteString("</" + tok.Data + ">")
+				} else {
+					open = append(open, tok.Data)
+				}
+			}
+
+		case html.EndTagToken:
+			tok := z.Token()
+			if droppedContentTags[tok.Data] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+			// penutup tanpa tag pembuka (mis. </div> yang menutup wrapper frontend) dibuang
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tok.Data {
+					closeFrom(i)
+					break
+				}
+			}
+		}
+	}
+}
+
+// tag block yang dipisahkan baris baru saat dikonversi ke teks biasa
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "hr": true, "li": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"tr": true, "table": true, "ul": true, "ol": true, "figure": true, "figcaption": true,
+}
+
+// HTMLToPlainText mengambil teks dari HTML tanpa tag
+func HTMLToPlainText(input string) string {
+	z := html.NewTokenizer(strings.NewReader(input))
+	var b strings.Builder
+	skipDepth := 0
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return collapseBlankLines(b.String())
+		case html.TextToken:
+			if skipDepth == 0 {
+				b.Write(z.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if droppedContentTags[tag] {
+				if tt == html.StartTagToken {
+					skipDepth++
+				} else if tt == html.EndTagToken && skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if blockTags[tag] {
+				b.WriteString("\n")
+			} else if tag == "td" || tag == "th" {
+				b.WriteString(" ")
+			}
+		}
+	}
+}
+
+// rapikan spasi di tiap baris dan batasi baris kosong berturut-turut
+func collapseBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	var out []string
+	blank := false
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
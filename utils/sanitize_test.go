@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSafeURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://codetech.id/a", true},
+		{"http://codetech.id", true},
+		{"mailto:halo@codetech.id", true},
+		{"tel:+62211234", true},
+		{"/artikel/satu", true},
+		{"#bagian", true},
+		{"?page=2", true},
+		{"gambar.png", true},
+		{"artikel/a:b", true},
+		{"javascript:alert(1)", false},
+		{"JaVaScRiPt:alert(1)", false},
+		{"  javascript:alert(1)", false},
+		{"java\tscript:alert(1)", false},
+		{"java\nscript:alert(1)", false},
+		{"\x01javascript:alert(1)", false},
+		{"data:text/html;base64,PHNjcmlwdD4=", false},
+		{"vbscript:msgbox(1)", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := SafeURL(tt.url); got != tt.want {
+			t.Errorf("SafeURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"allowed markup", `<p>Halo <strong>dunia</strong></p>`, `<p>Halo <strong>dunia</strong></p>`},
+		{"text escaped", `a < b & c`, `a &lt; b &amp; c`},
+
+		// URL berbahaya
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"entity encoded scheme", `<a href="javascript&#58;alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"hex entity scheme", `<a href="&#x6A;avascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"tab in scheme", "<a href=\"java\tscript:alert(1)\">x</a>", `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"entity tab in scheme", `<a href="java&#9;script:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"control char prefix", `<a href="&#1;javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"data src", `<img src="data:image/svg+xml;base64,PHN2Zz4=" alt="a">`, `<img alt="a">`},
+		{"safe href", `<a href="https://codetech.id/?a=1&amp;b=2">x</a>`, `<a href="https://codetech.id/?a=1&amp;b=2" rel="nofollow noopener noreferrer">x</a>`},
+
+		// tag berbahaya dibuang beserta isinya
+		{"script", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"svg", `<svg onload="alert(1)"><script>alert(1)</script></svg>ok`, `ok`},
+		{"style", `<style>body{display:none}</style><p>x</p>`, `<p>x</p>`},
+		{"iframe", `<iframe src="https://evil.test"></iframe>x`, `x`},
+		{"unknown tag keeps text", `<marquee>x</marquee>`, `x`},
+
+		// allow-list atribut
+		{"event handler", `<img src="/a.png" onerror="alert(1)">`, `<img src="/a.png">`},
+		{"onclick", `<p onclick="alert(1)">x</p>`, `<p>x</p>`},
+		{"style attribute", `<span style="background:url(javascript:alert(1))">x</span>`, `<span>x</span>`},
+		{"attribute not allowed for tag", `<p class="x" title="y">z</p>`, `<p>z</p>`},
+		{"attribute allowed for tag", `<code class="language-go">x</code>`, `<code class="language-go">x</code>`},
+		{"attribute value escaped", `<img src="/a.png" alt="&quot;><script>">`, `<img src="/a.png" alt="&#34;&gt;&lt;script&gt;">`},
+		{"empty title dropped", `<a href="/a" title="">x</a>`, `<a href="/a" rel="nofollow noopener noreferrer">x</a>`},
+		{"rel overridden", `<a href="/a" rel="opener">x</a>`, `<a href="/a" rel="nofollow noopener noreferrer">x</a>`},
+
+		// tag tidak seimbang
+		{"unclosed div", `<div><p>x`, `<div><p>x</p></div>`},
+		{"stray close", `x</div></p>`, `x`},
+		{"close outer closes inner", `<div><strong>x</div>y`, `<div><strong>x</strong></div>y`},
+		{"misnested", `<em><strong>x</em></strong>`, `<em><strong>x</strong></em>`},
+		{"self-closing non-void", `<div/>x`, `<div></div>x`},
+		{"void tags", `a<br>b<br/>c<hr>`, `a<br>b<br>c<hr>`},
+		{"unclosed script", `<p>x<script>alert(1)`, `<p>x</p>`},
+	}
+	for _, tt := range tests {
+		if got := SanitizeHTML(tt.input); got != tt.want {
+			t.Errorf("%s: SanitizeHTML(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeHTMLBalanced(t *testing.T) {
+	inputs := []string{
+		`<div><div><p>`,
+		`</div></div><p>x</p>`,
+		`<ul><li>a<li>b</ul></li></ul>`,
+		`<table><tr><td>a</table>`,
+		`<blockquote><pre><code>x`,
+	}
+	for _, input := range inputs {
+		got := SanitizeHTML(input)
+		for _, tag := range []string{"div", "p", "ul", "li", "table", "tr", "td", "blockquote", "pre", "code"} {
+			opened, closed := strings.Count(got, "<"+tag+">"), strings.Count(got, "</"+tag+">")
+			if opened != closed {
+				t.Errorf("SanitizeHTML(%q) = %q: %d <%s> vs %d </%s>", input, got, opened, tag, closed, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// skor >= SpamThreshold dianggap spam
+const SpamThreshold = 3
+
+var (
+	spamLinkPattern = regexp.MustCompile(`(?i)(https?://|www\.)`)
+	spamKeywords    = []string{
+		"viagra", "cialis", "casino", "slot gacor", "judi", "togel", "poker online",
+		"porn", "xxx", "payday loan", "pinjaman online", "crypto giveaway", "bitcoin doubler",
+		"seo service", "buy followers", "work from home",
+	}
+)
+
+// SpamScore menghitung skor heuristik spam dari sebuah teks bebas
+func SpamScore(text string) int {
+	score := 0
+	lower := strings.ToLower(text)
+
+	// banyak link
+	if links := len(spamLinkPattern.FindAllString(text, -1)); links > 2 {
+		score += 2
+	} else if links > 0 {
+		score++
+	}
+
+	// kata kunci spam umum
+	for _, kw := range spamKeywords {
+		if strings.Contains(lower, kw) {
+			score += 3
+			break
+		}
+	}
+
+	// markup BBCode / HTML link
+	if strings.Contains(lower, "[url") || strings.Contains(lower, "<a ") {
+		score += 2
+	}
+
+	// teks (cukup panjang) yang hampir seluruhnya huruf kapital
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters >= 20 && upper*10 >= letters*8 {
+		score++
+	}
+
+	return score
+}
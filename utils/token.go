@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// RandomToken membuat string acak url-safe dari n byte
+func RandomToken(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// HashToken untuk menyimpan token di database tanpa nilai aslinya
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func tokenSignature(payload, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignToken menambahkan tanda tangan HMAC: <payload>.<signature>
+func SignToken(payload, key string) string {
+	return payload + "." + tokenSignature(payload, key)
+}
+
+// VerifyToken mengecek tanda tangan dan mengembalikan payload-nya
+func VerifyToken(token, key string) (string, bool) {
+	i := strings.LastIndex(token, ".")
+	if i <= 0 {
+		return "", false
+	}
+	payload, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(tokenSignature(payload, key))) {
+		return "", false
+	}
+	return payload, true
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// secret RFC 6238 lampiran B (SHA1): ASCII "12345678901234567890"
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// kode 8 digit RFC 6238 lampiran B, dipotong ke 6 digit terakhir (modulo 10^6)
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	for _, v := range rfc6238Vectors {
+		step := TOTPStep(time.Unix(v.unix, 0))
+		if got := totpCode(key, step); got != v.code {
+			t.Errorf("totpCode at %d = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestValidateTOTPRFC6238(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		now := time.Unix(v.unix, 0)
+		step, ok := ValidateTOTP(rfc6238Secret, v.code, now, 0)
+		if !ok || step != TOTPStep(now) {
+			t.Errorf("ValidateTOTP at %d = (%d, %v), want (%d, true)", v.unix, step, ok, TOTPStep(now))
+		}
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	key := []byte("12345678901234567890")
+	current := TOTPStep(now)
+
+	// periode sebelum & sesudahnya masih diterima, lebih jauh ditolak
+	for _, offset := range []int64{-1, 1} {
+		if _, ok := ValidateTOTP(rfc6238Secret, totpCode(key, current+offset), now, 0); !ok {
+			t.Errorf("code for step offset %d rejected", offset)
+		}
+	}
+	for _, offset := range []int64{-2, 2} {
+		if _, ok := ValidateTOTP(rfc6238Secret, totpCode(key, current+offset), now, 0); ok {
+			t.Errorf("code for step offset %d accepted", offset)
+		}
+	}
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, now, 0); ok {
+			t.Errorf("malformed code %q accepted", code)
+		}
+	}
+	if _, ok := ValidateTOTP("not base32!", "050471", now, 0); ok {
+		t.Error("invalid secret accepted")
+	}
+}
+
+// kode yang sudah dipakai (periode <= totp_last_step) tidak bisa dipakai ulang
+func TestValidateTOTPReplay(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	key := []byte("12345678901234567890")
+	code := totpCode(key, TOTPStep(now))
+
+	lastStep, ok := ValidateTOTP(rfc6238Secret, code, now, 0)
+	if !ok {
+		t.Fatal("first use rejected")
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, code, now, lastStep); ok {
+		t.Error("replayed code accepted")
+	}
+	// masih dalam jendela ±1 periode, tetap ditolak
+	if _, ok := ValidateTOTP(rfc6238Secret, code, now.Add(totpPeriod*time.Second), lastStep); ok {
+		t.Error("replayed code accepted in next period")
+	}
+	// kode periode lama yang masih dalam jendela juga ditolak
+	if _, ok := ValidateTOTP(rfc6238Secret, totpCode(key, lastStep-1), now, lastStep); ok {
+		t.Error("code older than last step accepted")
+	}
+
+	// kode periode berikutnya tetap diterima
+	next := now.Add(totpPeriod * time.Second)
+	step, ok := ValidateTOTP(rfc6238Secret, totpCode(key, TOTPStep(next)), next, lastStep)
+	if !ok || step <= lastStep {
+		t.Errorf("next period code = (%d, %v), want step > %d", step, ok, lastStep)
+	}
+}